@@ -0,0 +1,287 @@
+package xlsxrange
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tealeg/xlsx"
+)
+
+// Sum returns total of numeric cells in selected range. Other cells are ignored like Excel SUM.
+func (r *Range) Sum() float64 {
+	return aggregateCells(r.allCells(), AggregateSum).(float64)
+}
+
+// Average returns average of numeric cells in selected range.
+// It returns error if there are no numeric cells.
+func (r *Range) Average() (float64, error) {
+	result := aggregateCells(r.allCells(), AggregateAverage)
+	if result == nil {
+		return 0, errors.New("There are no numeric cells to average")
+	}
+	return result.(float64), nil
+}
+
+// Min returns minimum value of numeric cells in selected range. It returns 0 if there are no numeric cells.
+func (r *Range) Min() float64 {
+	return aggregateCells(r.allCells(), AggregateMin).(float64)
+}
+
+// Max returns maximum value of numeric cells in selected range. It returns 0 if there are no numeric cells.
+func (r *Range) Max() float64 {
+	return aggregateCells(r.allCells(), AggregateMax).(float64)
+}
+
+// Count returns count of numeric cells in selected range like Excel COUNT.
+func (r *Range) Count() int {
+	return aggregateCells(r.allCells(), AggregateCount).(int)
+}
+
+// CountBlank returns count of blank cells in selected range like Excel COUNTBLANK.
+func (r *Range) CountBlank() int {
+	count := 0
+	for _, cell := range r.allCells() {
+		if cellKind(cell) == kindBlank {
+			count++
+		}
+	}
+	return count
+}
+
+// CountIf returns count of cells that satisfy Excel style criteria like ">1000" or "Open" (COUNTIF).
+func (r *Range) CountIf(criteria string) int {
+	match := compileCriterion(criteria, false)
+	count := 0
+	for _, cell := range r.allCells() {
+		if match(cell) {
+			count++
+		}
+	}
+	return count
+}
+
+// allCells returns cells in selected range as a flat slice. Missing cells are nil.
+func (r *Range) allCells() []*xlsx.Cell {
+	var result []*xlsx.Cell
+	rows := r.Rows(nil)
+	for rows.Next() {
+		result = append(result, rows.Row()...)
+	}
+	return result
+}
+
+// AggregateFunc is aggregation function of GroupBy and Pivot
+type AggregateFunc int
+
+const (
+	AggregateSum     AggregateFunc = iota // Total of numeric values
+	AggregateAverage                      // Average of numeric values
+	AggregateMin                          // Minimum of numeric values
+	AggregateMax                          // Maximum of numeric values
+	AggregateCount                        // Count of numeric values
+	AggregateCountA                       // Count of non blank values
+)
+
+func (f AggregateFunc) String() string {
+	switch f {
+	case AggregateSum:
+		return "Sum"
+	case AggregateAverage:
+		return "Average"
+	case AggregateMin:
+		return "Min"
+	case AggregateMax:
+		return "Max"
+	case AggregateCount:
+		return "Count"
+	case AggregateCountA:
+		return "CountA"
+	}
+	return "Unknown"
+}
+
+// aggregateCells aggregates cells. It returns float64 or int (for counts),
+// and nil for average of no numbers.
+func aggregateCells(cells []*xlsx.Cell, f AggregateFunc) interface{} {
+	sum := 0.0
+	count := 0
+	countA := 0
+	min := math.Inf(1)
+	max := math.Inf(-1)
+	for _, cell := range cells {
+		kind := cellKind(cell)
+		if kind != kindBlank {
+			countA++
+		}
+		if kind != kindNumber {
+			continue
+		}
+		value, _ := strconv.ParseFloat(cell.Value, 64)
+		sum += value
+		count++
+		min = math.Min(min, value)
+		max = math.Max(max, value)
+	}
+	switch f {
+	case AggregateAverage:
+		if count == 0 {
+			return nil
+		}
+		return sum / float64(count)
+	case AggregateMin:
+		if count == 0 {
+			return 0.0
+		}
+		return min
+	case AggregateMax:
+		if count == 0 {
+			return 0.0
+		}
+		return max
+	case AggregateCount:
+		return count
+	case AggregateCountA:
+		return countA
+	}
+	return sum
+}
+
+// AggregateSpec specifies a column of summary table
+type AggregateSpec struct {
+	Column string        // Source column name in header row
+	Func   AggregateFunc // Aggregation function
+	Name   string        // Column name in summary. Default is "Func(Column)" like "Sum(Amount)".
+}
+
+func (s AggregateSpec) name() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprintf("%s(%s)", s.Func, s.Column)
+}
+
+// Grouping is rows of a header-row range grouped by key columns
+type Grouping struct {
+	source     *Range
+	keyColumns []string
+	keyIndexes []int
+	keys       [][]interface{}
+	groups     map[string][]int // group key -> row numbers
+	err        error
+}
+
+// GroupBy groups data rows by values of key columns.
+//
+// The first row of selected range is used as header row. Groups are kept in order of first appearance.
+func (r *Range) GroupBy(keyColumns ...string) *Grouping {
+	header := r.header()
+	grouping := &Grouping{
+		source:     r,
+		keyColumns: keyColumns,
+		groups:     make(map[string][]int),
+	}
+	for _, name := range keyColumns {
+		index := indexOf(header, name)
+		if index == -1 {
+			grouping.err = fmt.Errorf("Key column '%s' is not found in header of %s", name, r.Format(true))
+			return grouping
+		}
+		grouping.keyIndexes = append(grouping.keyIndexes, index)
+	}
+	date1904 := r.File != nil && r.File.Date1904
+	rows := r.Rows(nil)
+	for rows.Next() {
+		if rows.RowNumber() == r.Row {
+			continue
+		}
+		key := make([]interface{}, len(grouping.keyIndexes))
+		for i, index := range grouping.keyIndexes {
+			key[i] = typedValue(rows.Row()[index], date1904)
+		}
+		id := groupID(key)
+		if _, ok := grouping.groups[id]; !ok {
+			grouping.keys = append(grouping.keys, key)
+		}
+		grouping.groups[id] = append(grouping.groups[id], rows.RowNumber())
+	}
+	return grouping
+}
+
+// Aggregate computes summary table. Each row has key column values and aggregated values.
+func (g *Grouping) Aggregate(specs ...AggregateSpec) (*Summary, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+	header := g.source.header()
+	summary := &Summary{Header: append([]string{}, g.keyColumns...)}
+	columns := make([]int, len(specs))
+	for i, spec := range specs {
+		columns[i] = indexOf(header, spec.Column)
+		if columns[i] == -1 {
+			return nil, fmt.Errorf("Column '%s' is not found in header of %s", spec.Column, g.source.Format(true))
+		}
+		summary.Header = append(summary.Header, spec.name())
+	}
+	for _, key := range g.keys {
+		rowNumbers := g.groups[groupID(key)]
+		row := append([]interface{}{}, key...)
+		for i, spec := range specs {
+			cells := make([]*xlsx.Cell, len(rowNumbers))
+			for j, rowNumber := range rowNumbers {
+				cells[j] = g.source.cellAt(rowNumber-1, g.source.Column+columns[i]-1)
+			}
+			row = append(row, aggregateCells(cells, spec.Func))
+		}
+		summary.Rows = append(summary.Rows, row)
+	}
+	return summary, nil
+}
+
+// groupID converts key values into map key
+func groupID(key []interface{}) string {
+	parts := make([]string, len(key))
+	for i, value := range key {
+		parts[i] = fmt.Sprintf("%T:%v", value, value)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// Summary is a computed table
+type Summary struct {
+	Header []string
+	Rows   [][]interface{} // Values are nil, float64, int, time.Time, bool or string
+}
+
+// WriteTo writes header and rows to left top corner of dest. It returns the range that was actually written.
+func (s *Summary) WriteTo(dest *Range) *Range {
+	for column, name := range s.Header {
+		dest.Sheet.Cell(dest.Row-1, dest.Column+column-1).SetString(name)
+	}
+	date1904 := dest.Sheet.File != nil && dest.Sheet.File.Date1904
+	for row, values := range s.Rows {
+		for column, value := range values {
+			setTypedValue(dest.Sheet.Cell(dest.Row+row, dest.Column+column-1), value, date1904)
+		}
+	}
+	return New(dest.Sheet, dest.Row, dest.Column, len(s.Rows)+1, len(s.Header))
+}
+
+// setTypedValue stores Go value into cell. It is the reverse of typedValue.
+// Dates are stored as serial numbers of the epoch of the workbook.
+func setTypedValue(cell *xlsx.Cell, value interface{}, date1904 bool) {
+	switch v := value.(type) {
+	case nil:
+		cell.SetString("")
+	case bool:
+		cell.SetBool(v)
+	case time.Time:
+		// Cell.SetDateTime uses the epoch of 1900 for new cells
+		cell.SetDateTimeWithFormat(xlsx.TimeToExcelTime(v.In(time.UTC), date1904), xlsx.DefaultDateTimeFormat)
+	default:
+		cell.SetValue(v)
+	}
+}
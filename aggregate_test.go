@@ -0,0 +1,94 @@
+package xlsxrange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestAggregations(t *testing.T) {
+	file := createTableFile()
+	amounts := New(file.Sheet["Data"], "B1:B7")
+
+	if amounts.Sum() != 8200 {
+		t.Errorf("Sum should be 8200, but %f", amounts.Sum())
+	}
+	if average, _ := amounts.Average(); average != 1640 {
+		t.Errorf("Average should be 1640, but %f", average)
+	}
+	if amounts.Min() != 500 {
+		t.Errorf("Min should be 500, but %f", amounts.Min())
+	}
+	if amounts.Max() != 3000 {
+		t.Errorf("Max should be 3000, but %f", amounts.Max())
+	}
+	if amounts.Count() != 5 {
+		t.Errorf("Count should be 5, but %d", amounts.Count())
+	}
+	if amounts.CountBlank() != 1 {
+		t.Errorf("CountBlank should be 1, but %d", amounts.CountBlank())
+	}
+	if amounts.CountIf(">1000") != 4 {
+		t.Errorf("CountIf should be 4, but %d", amounts.CountIf(">1000"))
+	}
+	if _, err := New(file.Sheet["Data"], "A1:A6").Average(); err == nil {
+		t.Errorf("Average should return error without numbers")
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+
+	summary, err := New(sheet, "A1:C6").GroupBy("Status").Aggregate(
+		AggregateSpec{Column: "Amount", Func: AggregateSum},
+		AggregateSpec{Column: "Owner", Func: AggregateCountA, Name: "Owners"},
+	)
+	if err != nil {
+		t.Errorf("Aggregate should not return error, but %s", err)
+	}
+	if len(summary.Rows) != 3 {
+		t.Errorf("summary should have 3 rows, but %d", len(summary.Rows))
+	}
+	if summary.Header[1] != "Sum(Amount)" {
+		t.Errorf("header should be 'Sum(Amount)', but %s", summary.Header[1])
+	}
+	if summary.Rows[0][0] != "Open" || summary.Rows[0][1] != 4000.0 || summary.Rows[0][2] != 3 {
+		t.Errorf("first row should be [Open 4000 3], but %v", summary.Rows[0])
+	}
+
+	written := summary.WriteTo(New(sheet, "E1"))
+	if written.Format(false) != "E1:G4" {
+		t.Errorf("written range should be 'E1:G4', but %s", written.Format(false))
+	}
+	if written.GetCellAt(2, 1).Value != "3000" {
+		t.Errorf("F3 should be '3000', but %s", written.GetCellAt(2, 1).Value)
+	}
+
+	_, err = New(sheet, "A1:C6").GroupBy("Missing").Aggregate()
+	if err == nil {
+		t.Errorf("Aggregate should return error for missing key column")
+	}
+}
+
+func TestSummaryWriteToWithDate1904(t *testing.T) {
+	file := xlsx.NewFile()
+	file.Date1904 = true
+	sheet, _ := file.AddSheet("Data")
+	sheet.Cell(0, 0).SetString("Date")
+	sheet.Cell(0, 1).SetString("Amount")
+	date := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+	for row := 1; row < 3; row++ {
+		sheet.Cell(row, 0).SetDateTimeWithFormat(xlsx.TimeToExcelTime(date, true), xlsx.DefaultDateFormat)
+		sheet.Cell(row, 1).SetInt(row * 100)
+	}
+	summary, err := New(sheet, "A1:B3").GroupBy("Date").Aggregate(AggregateSpec{Column: "Amount", Func: AggregateSum})
+	if err != nil {
+		t.Fatalf("Aggregate should succeed, but %s", err)
+	}
+	written := summary.WriteTo(New(sheet, "D1"))
+	if value := typedValue(written.GetCellAt(1, 0), true); value != date {
+		t.Errorf("date should be written in the epoch of the workbook, but %v", value)
+	}
+}
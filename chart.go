@@ -0,0 +1,210 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+// ChartType is a kind of chart
+type ChartType int
+
+const (
+	ChartColumn ChartType = iota // Vertical bars
+	ChartBar                     // Horizontal bars
+	ChartLine
+	ChartPie
+)
+
+// ChartSeries is a data series of a chart
+type ChartSeries struct {
+	Name       string
+	Categories *Range // Category labels. It can be nil.
+	Values     *Range
+}
+
+// ChartBuilder builds a chart from ranges.
+//
+//	err := xlsxrange.NewChart(xlsxrange.ChartColumn).Title("Sales").
+//		Series("Amount", xlsxrange.New(sheet, "A2:A6"), xlsxrange.New(sheet, "B2:B6")).
+//		PlaceAt(xlsxrange.New(sheet, "E2:L16"))
+//
+// Charts are kept by this package because tealeg/xlsx doesn't support charts.
+// Use xlsxrange.Save or xlsxrange.Write to write them into the file.
+type ChartBuilder struct {
+	chartType ChartType
+	title     string
+	series    []ChartSeries
+	noLegend  bool
+	anchor    *Range
+}
+
+// NewChart returns chart builder
+func NewChart(chartType ChartType) *ChartBuilder {
+	return &ChartBuilder{chartType: chartType}
+}
+
+// Title sets title of the chart
+func (b *ChartBuilder) Title(title string) *ChartBuilder {
+	b.title = title
+	return b
+}
+
+// Series adds data series. categories can be nil.
+func (b *ChartBuilder) Series(name string, categories, values *Range) *ChartBuilder {
+	b.series = append(b.series, ChartSeries{Name: name, Categories: categories, Values: values})
+	return b
+}
+
+// Legend shows or hides legend. Default is shown.
+func (b *ChartBuilder) Legend(visible bool) *ChartBuilder {
+	b.noLegend = !visible
+	return b
+}
+
+// PlaceAt places the chart over anchor range. If anchor is a single cell, the chart
+// is placed from the cell with default size (8 columns and 15 rows).
+//
+// Charts are kept by this package because tealeg/xlsx doesn't support them.
+// Use xlsxrange.Save or xlsxrange.Write to write them into the file.
+func (b *ChartBuilder) PlaceAt(anchor *Range) error {
+	if len(b.series) == 0 {
+		return fmt.Errorf("Chart at %s needs at least one series", anchor.Format(true))
+	}
+	for _, series := range b.series {
+		if series.Values == nil {
+			return fmt.Errorf("Series '%s' of chart at %s doesn't have values", series.Name, anchor.Format(true))
+		}
+		if series.Categories != nil {
+			valueRows, valueColumns := series.Values.clamp().size()
+			categoryRows, categoryColumns := series.Categories.clamp().size()
+			if valueRows*valueColumns != categoryRows*categoryColumns {
+				return fmt.Errorf("Series '%s' of chart at %s has %d values but %d categories", series.Name,
+					anchor.Format(true), valueRows*valueColumns, categoryRows*categoryColumns)
+			}
+		}
+	}
+	chart := *b
+	chart.series = append([]ChartSeries{}, b.series...)
+	chart.anchor = anchor
+	if anchor.NumRows == 1 && anchor.NumColumns == 1 {
+		chart.anchor = New(anchor.Sheet, anchor.Row, anchor.Column, 15, 8)
+	}
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext := sheetExtensionOf(anchor.Sheet)
+	ext.charts = append(ext.charts, &chart)
+	return nil
+}
+
+const (
+	chartNamespace   = "http://schemas.openxmlformats.org/drawingml/2006/chart"
+	drawingNamespace = "http://schemas.openxmlformats.org/drawingml/2006/main"
+)
+
+// drawing writes drawing part and chart parts of the sheet and returns drawing element
+func (b *packageBuilder) drawing(sheetPart string, charts []*ChartBuilder) string {
+	var buffer bytes.Buffer
+	buffer.WriteString(`<xdr:wsDr xmlns:xdr="http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing" xmlns:a="` + drawingNamespace + `">`)
+	var chartParts []string
+	for _, chart := range charts {
+		chartParts = append(chartParts, b.addPart("xl/charts/chart%d.xml",
+			"application/vnd.openxmlformats-officedocument.drawingml.chart+xml", chart.chartSpace()))
+	}
+	drawingPart := b.addPart("xl/drawings/drawing%d.xml", "application/vnd.openxmlformats-officedocument.drawing+xml", "")
+	for i, chart := range charts {
+		anchor := chart.anchor.clamp()
+		rowCount, columnCount := anchor.size()
+		id := b.addRelationship(drawingPart, "chart", chartParts[i])
+		fmt.Fprintf(&buffer, `<xdr:twoCellAnchor>`+
+			`<xdr:from><xdr:col>%d</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>%d</xdr:row><xdr:rowOff>0</xdr:rowOff></xdr:from>`+
+			`<xdr:to><xdr:col>%d</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>%d</xdr:row><xdr:rowOff>0</xdr:rowOff></xdr:to>`+
+			`<xdr:graphicFrame macro=""><xdr:nvGraphicFramePr><xdr:cNvPr id="%d" name="Chart %d"/><xdr:cNvGraphicFramePr/></xdr:nvGraphicFramePr>`+
+			`<xdr:xfrm><a:off x="0" y="0"/><a:ext cx="0" cy="0"/></xdr:xfrm>`+
+			`<a:graphic><a:graphicData uri="%s"><c:chart xmlns:c="%s" xmlns:r="%s" r:id="%s"/></a:graphicData></a:graphic>`+
+			`</xdr:graphicFrame><xdr:clientData/></xdr:twoCellAnchor>`,
+			anchor.Column-1, anchor.Row-1, anchor.Column-1+columnCount, anchor.Row-1+rowCount,
+			i+2, i+1, chartNamespace, chartNamespace, relationshipNamespace, id)
+	}
+	buffer.WriteString(`</xdr:wsDr>`)
+	b.parts[drawingPart] += buffer.String()
+	id := b.addRelationship(sheetPart, "drawing", drawingPart)
+	return fmt.Sprintf(`<drawing r:id="%s"/>`, id)
+}
+
+// chartSpace returns XML of chart part
+func (b *ChartBuilder) chartSpace() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(`<c:chartSpace xmlns:c="` + chartNamespace + `" xmlns:a="` + drawingNamespace + `"><c:chart>`)
+	if b.title != "" {
+		buffer.WriteString(`<c:title><c:tx><c:rich><a:bodyPr/><a:p><a:r><a:t>`)
+		xml.EscapeText(&buffer, []byte(b.title))
+		buffer.WriteString(`</a:t></a:r></a:p></c:rich></c:tx><c:overlay val="0"/></c:title><c:autoTitleDeleted val="0"/>`)
+	} else {
+		buffer.WriteString(`<c:autoTitleDeleted val="1"/>`)
+	}
+	buffer.WriteString(`<c:plotArea><c:layout/>`)
+	switch b.chartType {
+	case ChartColumn, ChartBar:
+		direction := "col"
+		if b.chartType == ChartBar {
+			direction = "bar"
+		}
+		fmt.Fprintf(&buffer, `<c:barChart><c:barDir val="%s"/><c:grouping val="clustered"/><c:varyColors val="0"/>`, direction)
+		b.writeSeries(&buffer, `<c:invertIfNegative val="0"/>`, "")
+		buffer.WriteString(`<c:gapWidth val="150"/><c:axId val="1"/><c:axId val="2"/></c:barChart>`)
+	case ChartLine:
+		buffer.WriteString(`<c:lineChart><c:grouping val="standard"/><c:varyColors val="0"/>`)
+		b.writeSeries(&buffer, `<c:marker><c:symbol val="none"/></c:marker>`, `<c:smooth val="0"/>`)
+		buffer.WriteString(`<c:marker val="1"/><c:axId val="1"/><c:axId val="2"/></c:lineChart>`)
+	case ChartPie:
+		buffer.WriteString(`<c:pieChart><c:varyColors val="1"/>`)
+		b.writeSeries(&buffer, "", "")
+		buffer.WriteString(`<c:firstSliceAng val="0"/></c:pieChart>`)
+	}
+	if b.chartType != ChartPie {
+		categoryPosition, valuePosition := "b", "l"
+		if b.chartType == ChartBar {
+			categoryPosition, valuePosition = "l", "b"
+		}
+		fmt.Fprintf(&buffer, `<c:catAx><c:axId val="1"/><c:scaling><c:orientation val="minMax"/></c:scaling>`+
+			`<c:delete val="0"/><c:axPos val="%s"/><c:crossAx val="2"/></c:catAx>`+
+			`<c:valAx><c:axId val="2"/><c:scaling><c:orientation val="minMax"/></c:scaling>`+
+			`<c:delete val="0"/><c:axPos val="%s"/><c:majorGridlines/><c:crossAx val="1"/></c:valAx>`, categoryPosition, valuePosition)
+	}
+	buffer.WriteString(`</c:plotArea>`)
+	if !b.noLegend {
+		buffer.WriteString(`<c:legend><c:legendPos val="r"/><c:overlay val="0"/></c:legend>`)
+	}
+	buffer.WriteString(`<c:plotVisOnly val="1"/></c:chart></c:chartSpace>`)
+	return buffer.String()
+}
+
+// writeSeries writes ser elements. beforeCategories and afterValues are elements that depend on chart type.
+func (b *ChartBuilder) writeSeries(buffer *bytes.Buffer, beforeCategories, afterValues string) {
+	for i, series := range b.series {
+		fmt.Fprintf(buffer, `<c:ser><c:idx val="%d"/><c:order val="%d"/>`, i, i)
+		if series.Name != "" {
+			buffer.WriteString(`<c:tx><c:v>`)
+			xml.EscapeText(buffer, []byte(series.Name))
+			buffer.WriteString(`</c:v></c:tx>`)
+		}
+		buffer.WriteString(beforeCategories)
+		if series.Categories != nil {
+			categories := series.Categories.clamp()
+			reference := "strRef"
+			// categories may start below the populated rows, so the cell can be missing
+			if cellKind(categories.cellAt(categories.Row-1, categories.Column-1)) == kindNumber {
+				reference = "numRef"
+			}
+			fmt.Fprintf(buffer, `<c:cat><c:%s><c:f>`, reference)
+			xml.EscapeText(buffer, []byte(referenceFormula(categories)))
+			fmt.Fprintf(buffer, `</c:f></c:%s></c:cat>`, reference)
+		}
+		buffer.WriteString(`<c:val><c:numRef><c:f>`)
+		xml.EscapeText(buffer, []byte(referenceFormula(series.Values.clamp())))
+		buffer.WriteString(`</c:f></c:numRef></c:val>`)
+		buffer.WriteString(afterValues)
+		buffer.WriteString(`</c:ser>`)
+	}
+}
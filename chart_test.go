@@ -0,0 +1,108 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestChart(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	err := NewChart(ChartColumn).Title("Sales & Costs").
+		Series("Amount", New(sheet, "A2:A6"), New(sheet, "B2:B6")).
+		PlaceAt(New(sheet, "E2:L16"))
+	if err != nil {
+		t.Fatalf("PlaceAt should succeed, but %s", err.Error())
+	}
+	report, _ := file.AddSheet("Q1 Report")
+	if err := NewChart(ChartPie).Series("", New(sheet, "A2:A6"), New(sheet, "B2:B6")).PlaceAt(New(report, "B2")); err != nil {
+		t.Fatalf("PlaceAt should succeed, but %s", err.Error())
+	}
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	chartXML := parts["xl/charts/chart1.xml"]
+	expected := []string{
+		`<c:barDir val="col"/>`,
+		`<a:t>Sales &amp; Costs</a:t>`,
+		`<c:tx><c:v>Amount</c:v></c:tx>`,
+		`<c:cat><c:strRef><c:f>Data!$A$2:$A$6</c:f></c:strRef></c:cat>`,
+		`<c:val><c:numRef><c:f>Data!$B$2:$B$6</c:f></c:numRef></c:val>`,
+		`<c:catAx>`,
+	}
+	for _, text := range expected {
+		if !strings.Contains(chartXML, text) {
+			t.Errorf("chart part should contain %s, but %s", text, chartXML)
+		}
+	}
+	drawingXML := parts["xl/drawings/drawing1.xml"]
+	if !strings.Contains(drawingXML, `<xdr:from><xdr:col>4</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>1</xdr:row>`) ||
+		!strings.Contains(drawingXML, `<xdr:to><xdr:col>12</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>16</xdr:row>`) {
+		t.Errorf("drawing should be anchored at E2:L16, but %s", drawingXML)
+	}
+	if xml := parts["xl/worksheets/sheet1.xml"]; !strings.Contains(xml, `<drawing r:id="rId1"/>`) {
+		t.Errorf("sheet should refer drawing part, but %s", xml)
+	}
+	if rels := parts["xl/drawings/_rels/drawing1.xml.rels"]; !strings.Contains(rels, `Target="../charts/chart1.xml"`) {
+		t.Errorf("drawing relationships should have chart, but %s", rels)
+	}
+	if pieXML := parts["xl/charts/chart2.xml"]; !strings.Contains(pieXML, `<c:pieChart>`) || strings.Contains(pieXML, `<c:catAx>`) {
+		t.Errorf("second chart should be pie chart without axes, but %s", pieXML)
+	}
+	if drawingXML := parts["xl/drawings/drawing2.xml"]; !strings.Contains(drawingXML, `<xdr:to><xdr:col>9</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>16</xdr:row>`) {
+		t.Errorf("chart at single cell should have default size, but %s", drawingXML)
+	}
+
+	var buffer bytes.Buffer
+	if err := Write(file, &buffer); err != nil {
+		t.Fatalf("Write should succeed, but %s", err.Error())
+	}
+	if _, err := xlsx.OpenBinary(buffer.Bytes()); err != nil {
+		t.Errorf("written file should be readable, but %s", err.Error())
+	}
+}
+
+func TestChartQuotesSheetName(t *testing.T) {
+	file := xlsx.NewFile()
+	sheet, _ := file.AddSheet("Q1 Sales")
+	New(sheet, "A1").ReadCSV(strings.NewReader("Month,Amount\nJan,100\nFeb,200"), &ImportOptions{InferTypes: true})
+	NewChart(ChartLine).Series("Amount", New(sheet, "A2:A3"), New(sheet, "B2:B3")).PlaceAt(New(sheet, "D2:H10"))
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	if chartXML := parts["xl/charts/chart1.xml"]; !strings.Contains(chartXML, `<c:f>&#39;Q1 Sales&#39;!$B$2:$B$3</c:f>`) {
+		t.Errorf("series formula should quote sheet name, but %s", chartXML)
+	}
+}
+
+func TestChartBeyondData(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	err := NewChart(ChartLine).Series("Amount", New(sheet, "A20:A30"), New(sheet, "B20:B30")).PlaceAt(New(sheet, "E2:L16"))
+	if err != nil {
+		t.Fatalf("PlaceAt should succeed, but %s", err.Error())
+	}
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	if chartXML := parts["xl/charts/chart1.xml"]; !strings.Contains(chartXML, `<c:cat><c:strRef><c:f>Data!$A$20:$A$30</c:f>`) {
+		t.Errorf("series beyond data should refer the range, but %s", chartXML)
+	}
+}
+
+func TestChartErrors(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	if err := NewChart(ChartBar).PlaceAt(New(sheet, "E2")); err == nil {
+		t.Errorf("chart without series should be error")
+	}
+	if err := NewChart(ChartBar).Series("Amount", New(sheet, "A2:A4"), New(sheet, "B2:B6")).PlaceAt(New(sheet, "E2")); err == nil {
+		t.Errorf("series with different number of categories should be error")
+	}
+}
@@ -0,0 +1,351 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tealeg/xlsx"
+)
+
+// Violation is an invalid cell value found by CheckValidations or CheckSchema
+type Violation struct {
+	Address string // A1 address of the cell
+	Column  string // Header name of the column. It is empty for CheckValidations.
+	Value   string
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Column != "" {
+		return fmt.Sprintf("%s (%s): %s", v.Address, v.Column, v.Message)
+	}
+	return fmt.Sprintf("%s: %s", v.Address, v.Message)
+}
+
+// Violations is a list of violations
+type Violations []Violation
+
+// Error returns all violations in lines. It makes Violations usable as an error.
+func (v Violations) Error() string {
+	var buffer bytes.Buffer
+	for i, violation := range v {
+		if i > 0 {
+			buffer.WriteByte('\n')
+		}
+		buffer.WriteString(violation.String())
+	}
+	return buffer.String()
+}
+
+// CheckValidations checks all cells in selected range against their data validation rules.
+//
+// Lists, whole numbers, decimals, dates, times and text lengths are checked. Bounds should be
+// constant numbers, DATE(y,m,d) or TIME(h,m,s). Custom formulas and other formulas are not evaluated.
+// If the rule has error message, it is used as the message of the violation.
+func (r *Range) CheckValidations() Violations {
+	rowCount, columnCount := r.size()
+	var result Violations
+	for row := 0; row < rowCount; row++ {
+		for column := 0; column < columnCount; column++ {
+			absRow := r.Row + row
+			absColumn := r.Column + column
+			rule := r.validationRuleAt(absRow, absColumn)
+			if rule == nil {
+				continue
+			}
+			cell := r.cellAt(absRow-1, absColumn-1)
+			message := r.checkRule(r.fromRule(rule), cell)
+			if message == "" {
+				continue
+			}
+			if rule.Error != nil && *rule.Error != "" {
+				message = *rule.Error
+			}
+			result = append(result, Violation{
+				Address: cellName(absRow, absColumn),
+				Value:   cellContent(cell),
+				Message: message,
+			})
+		}
+	}
+	return result
+}
+
+// checkRule returns message if the cell violates the rule
+func (r *Range) checkRule(validation *Validation, cell *xlsx.Cell) string {
+	if cellKind(cell) == kindBlank {
+		if validation.AllowBlank {
+			return ""
+		}
+		return "Value is required"
+	}
+	switch validation.Type {
+	case ValidateList:
+		var choices []string
+		if validation.ListRange != nil {
+			// list range may be larger than the data, so missing cells are skipped
+			area := validation.ListRange.clamp()
+			rowCount, columnCount := area.size()
+			for row := 0; row < rowCount; row++ {
+				for column := 0; column < columnCount; column++ {
+					if choice := area.cellAt(area.Row+row-1, area.Column+column-1); choice != nil {
+						choices = append(choices, choice.Value)
+					}
+				}
+			}
+		} else if validation.List != nil {
+			choices = validation.List
+		} else {
+			return ""
+		}
+		for _, choice := range choices {
+			if strings.EqualFold(strings.TrimSpace(choice), cell.Value) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("'%s' is not in the list", cell.Value)
+	case ValidateCustom:
+		return ""
+	case ValidateTextLength:
+		return checkBounds(float64(utf8.RuneCountInString(cell.Value)), validation, "Length of text", false)
+	}
+	if cellKind(cell) != kindNumber {
+		return fmt.Sprintf("'%s' is not a number", cell.Value)
+	}
+	value, _ := strconv.ParseFloat(cell.Value, 64)
+	switch validation.Type {
+	case ValidateWhole:
+		if value != math.Trunc(value) {
+			return fmt.Sprintf("'%s' is not a whole number", cell.Value)
+		}
+	case ValidateDate:
+		value = math.Floor(value)
+	case ValidateTime:
+		value = value - math.Floor(value)
+	}
+	return checkBounds(value, validation, "Value", r.File != nil && r.File.Date1904)
+}
+
+var dateFormulaPattern = regexp.MustCompile(`^(?i)DATE\((\d+),(\d+),(\d+)\)$`)
+var timeFormulaPattern = regexp.MustCompile(`^(?i)TIME\((\d+),(\d+),(\d+)\)$`)
+
+// constantFormula returns value of constant number, DATE(y,m,d) or TIME(h,m,s).
+// DATE(y,m,d) is converted with the epoch of the workbook.
+func constantFormula(formula string, date1904 bool) (float64, bool) {
+	formula = strings.Replace(strings.TrimPrefix(formula, "="), " ", "", -1)
+	if value, err := strconv.ParseFloat(formula, 64); err == nil {
+		return value, true
+	}
+	if match := dateFormulaPattern.FindStringSubmatch(formula); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		day, _ := strconv.Atoi(match[3])
+		return xlsx.TimeToExcelTime(time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC), date1904), true
+	}
+	if match := timeFormulaPattern.FindStringSubmatch(formula); match != nil {
+		hour, _ := strconv.Atoi(match[1])
+		minute, _ := strconv.Atoi(match[2])
+		second, _ := strconv.Atoi(match[3])
+		return float64(hour*3600+minute*60+second) / 86400, true
+	}
+	return 0, false
+}
+
+// checkBounds returns message if value doesn't satisfy operator of validation
+func checkBounds(value float64, validation *Validation, subject string, date1904 bool) string {
+	formula1, ok := constantFormula(validation.Formula1, date1904)
+	if !ok {
+		return ""
+	}
+	formula2, ok := constantFormula(validation.Formula2, date1904)
+	operator := validation.Operator
+	if operator == "" {
+		operator = OperatorBetween
+	}
+	if !ok && (operator == OperatorBetween || operator == OperatorNotBetween) {
+		return ""
+	}
+	valid := true
+	expected := ""
+	switch operator {
+	case OperatorEqual:
+		valid, expected = value == formula1, "equal to "+validation.Formula1
+	case OperatorNotEqual:
+		valid, expected = value != formula1, "not equal to "+validation.Formula1
+	case OperatorGreaterThan:
+		valid, expected = value > formula1, "greater than "+validation.Formula1
+	case OperatorGreaterThanOrEqual:
+		valid, expected = value >= formula1, "greater than or equal to "+validation.Formula1
+	case OperatorLessThan:
+		valid, expected = value < formula1, "less than "+validation.Formula1
+	case OperatorLessThanOrEqual:
+		valid, expected = value <= formula1, "less than or equal to "+validation.Formula1
+	case OperatorBetween:
+		valid, expected = formula1 <= value && value <= formula2, "between "+validation.Formula1+" and "+validation.Formula2
+	case OperatorNotBetween:
+		valid, expected = value < formula1 || formula2 < value, "not between "+validation.Formula1+" and "+validation.Formula2
+	}
+	if valid {
+		return ""
+	}
+	return fmt.Sprintf("%s should be %s", subject, expected)
+}
+
+// SchemaType is a value type of ColumnSchema
+type SchemaType int
+
+const (
+	SchemaAny     SchemaType = iota // Any values
+	SchemaString                    // Text. Numbers are also accepted as text.
+	SchemaNumber                    // Numbers or numeric text
+	SchemaInteger                   // Whole numbers or whole number text
+	SchemaDate                      // Date formatted cells or text in DefaultDateLayouts
+	SchemaBool                      // Booleans or "true"/"false" text
+)
+
+// ColumnSchema is a rule of a column for CheckSchema.
+//
+// Min and Max accept int, float64 and time.Time. They are compared with numbers, dates, or
+// length of text for SchemaString and SchemaAny.
+type ColumnSchema struct {
+	Name     string // Header name
+	Type     SchemaType
+	Required bool
+	Pattern  string   // Regular expression that whole text should match
+	Enum     []string // Allowed values
+	Min      interface{}
+	Max      interface{}
+}
+
+// CheckSchema checks data rows in header-row range against schema of columns.
+// It returns error if schema is invalid or columns in schema are not found in header.
+func (r *Range) CheckSchema(schema []ColumnSchema) (Violations, error) {
+	header := r.header()
+	indexes := make([]int, len(schema))
+	patterns := make([]*regexp.Regexp, len(schema))
+	for i, column := range schema {
+		indexes[i] = indexOf(header, column.Name)
+		if indexes[i] == -1 {
+			return nil, fmt.Errorf("Column '%s' is not found in header of %s", column.Name, r.Format(true))
+		}
+		if column.Pattern != "" {
+			pattern, err := regexp.Compile("^(?:" + column.Pattern + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("Pattern of column '%s' is invalid: %s", column.Name, err.Error())
+			}
+			patterns[i] = pattern
+		}
+		for _, bound := range []interface{}{column.Min, column.Max} {
+			if _, ok := boundValue(bound, false); bound != nil && !ok {
+				return nil, fmt.Errorf("Min and Max of column '%s' should be int, float64 or time.Time, but %T", column.Name, bound)
+			}
+		}
+	}
+	date1904 := r.File != nil && r.File.Date1904
+	var result Violations
+	rows := r.Rows(&IterateOptions{SkipEmpty: true})
+	for rows.Next() {
+		if rows.RowNumber() == r.Row {
+			continue
+		}
+		for i, column := range schema {
+			cell := rows.Row()[indexes[i]]
+			message := column.check(cell, patterns[i], date1904)
+			if message != "" {
+				result = append(result, Violation{
+					Address: cellName(rows.RowNumber(), r.Column+indexes[i]),
+					Column:  column.Name,
+					Value:   cellContent(cell),
+					Message: message,
+				})
+			}
+		}
+	}
+	return result, rows.Err()
+}
+
+// check returns message if the cell violates the schema
+func (s ColumnSchema) check(cell *xlsx.Cell, pattern *regexp.Regexp, date1904 bool) string {
+	if cellKind(cell) == kindBlank {
+		if s.Required {
+			return "Value is required"
+		}
+		return ""
+	}
+	text := cell.Value
+	var value float64
+	measured := false
+	switch s.Type {
+	case SchemaNumber, SchemaInteger:
+		number, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil || cellKind(cell) == kindBool {
+			return fmt.Sprintf("'%s' is not a number", text)
+		}
+		if s.Type == SchemaInteger && number != math.Trunc(number) {
+			return fmt.Sprintf("'%s' is not an integer", text)
+		}
+		value, measured = number, true
+	case SchemaDate:
+		date, ok := typedValue(cell, date1904).(time.Time)
+		if !ok {
+			for _, layout := range DefaultDateLayouts {
+				if parsed, err := time.Parse(layout, strings.TrimSpace(text)); err == nil {
+					date, ok = parsed, true
+					break
+				}
+			}
+		}
+		if !ok {
+			return fmt.Sprintf("'%s' is not a date", text)
+		}
+		value, measured = xlsx.TimeToExcelTime(date, date1904), true
+	case SchemaBool:
+		if cellKind(cell) != kindBool && !strings.EqualFold(text, "true") && !strings.EqualFold(text, "false") {
+			return fmt.Sprintf("'%s' is not a boolean", text)
+		}
+	}
+	if pattern != nil && !pattern.MatchString(text) {
+		return fmt.Sprintf("'%s' doesn't match pattern %s", text, s.Pattern)
+	}
+	if len(s.Enum) > 0 && indexOf(s.Enum, text) == -1 {
+		return fmt.Sprintf("'%s' should be one of %s", text, strings.Join(s.Enum, ", "))
+	}
+	subject := "Value"
+	if !measured {
+		value = float64(utf8.RuneCountInString(text))
+		subject = "Length of text"
+	}
+	if min, ok := boundValue(s.Min, date1904); ok && value < min {
+		return fmt.Sprintf("%s should be %s or more", subject, boundText(s.Min))
+	}
+	if max, ok := boundValue(s.Max, date1904); ok && value > max {
+		return fmt.Sprintf("%s should be %s or less", subject, boundText(s.Max))
+	}
+	return ""
+}
+
+// boundValue converts Min or Max of ColumnSchema into number. Dates are converted with the epoch of the workbook.
+func boundValue(bound interface{}, date1904 bool) (float64, bool) {
+	switch v := bound.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	case time.Time:
+		return xlsx.TimeToExcelTime(v, date1904), true
+	}
+	return 0, false
+}
+
+func boundText(bound interface{}) string {
+	if t, ok := bound.(time.Time); ok {
+		return t.Format("2006-01-02")
+	}
+	return fmt.Sprintf("%v", bound)
+}
@@ -0,0 +1,126 @@
+package xlsxrange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestCheckValidations(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	New(sheet, "A2:A6").AddValidation(&Validation{Type: ValidateList, List: []string{"Open", "Closed"}})
+	New(sheet, "B2:B6").AddValidation(&Validation{Type: ValidateWhole, Formula1: "1000", Formula2: "2500", ErrorMessage: "Amount should be 1000-2500"})
+	New(sheet, "C2:C6").AddValidation(&Validation{Type: ValidateTextLength, Operator: OperatorLessThanOrEqual, Formula1: "4"})
+
+	violations := New(sheet, "A1:C6").CheckValidations()
+	if len(violations) != 5 {
+		t.Fatalf("CheckValidations should find 5 violations, but %d: %s", len(violations), violations.Error())
+	}
+	expected := []string{
+		"C2: Length of text should be less than or equal to 4",
+		"B3: Amount should be 1000-2500",
+		"B4: Amount should be 1000-2500",
+		"C4: Length of text should be less than or equal to 4",
+		"A6: 'Pending' is not in the list",
+	}
+	for i, violation := range violations {
+		if violation.String() != expected[i] {
+			t.Errorf("violation %d should be '%s', but '%s'", i, expected[i], violation.String())
+		}
+	}
+}
+
+func TestCheckValidationsWithDate(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	sheet.Cell(1, 3).SetDate(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))
+	sheet.Cell(2, 3).SetDate(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	New(sheet, "D2:D3").AddValidation(&Validation{
+		Type:     ValidateDate,
+		Operator: OperatorGreaterThanOrEqual,
+		Formula1: DateFormula(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	violations := New(sheet, "D1:D3").CheckValidations()
+	if len(violations) != 1 || violations[0].Address != "D2" {
+		t.Errorf("CheckValidations should find a date before lower bound, but %v", violations)
+	}
+}
+
+func TestCheckValidationsWithDate1904(t *testing.T) {
+	file := createTableFile()
+	file.Date1904 = true
+	sheet := file.Sheet["Data"]
+	sheet.Cell(1, 3).SetDateTimeWithFormat(xlsx.TimeToExcelTime(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), true), "yyyy-mm-dd")
+	sheet.Cell(2, 3).SetDateTimeWithFormat(xlsx.TimeToExcelTime(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), true), "yyyy-mm-dd")
+	New(sheet, "D2:D3").AddValidation(&Validation{
+		Type:     ValidateDate,
+		Operator: OperatorGreaterThanOrEqual,
+		Formula1: DateFormula(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	violations := New(sheet, "D1:D3").CheckValidations()
+	if len(violations) != 1 || violations[0].Address != "D2" {
+		t.Errorf("CheckValidations should compare dates in 1904 epoch, but %v", violations)
+	}
+}
+
+func TestCheckValidationsWithLargeListRange(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	lists, _ := file.AddSheet("Lists")
+	lists.Cell(0, 0).SetString("Open")
+	lists.Cell(1, 0).SetString("Closed")
+	New(sheet, "A2:A6").AddValidation(&Validation{Type: ValidateList, ListRange: New(lists, "A1:A100")})
+
+	violations := New(sheet, "A2:A6").CheckValidations()
+	if len(violations) != 1 || violations[0].Address != "A6" {
+		t.Errorf("CheckValidations should use cells in list range, but %v", violations)
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	New(sheet, "C4").GetCell().SetString("")
+	violations, err := New(sheet, "A1:C6").CheckSchema([]ColumnSchema{
+		{Name: "Status", Enum: []string{"Open", "Closed"}},
+		{Name: "Amount", Type: SchemaInteger, Min: 1000},
+		{Name: "Owner", Required: true, Pattern: "[a-z]+", Max: 4},
+	})
+	if err != nil {
+		t.Fatalf("CheckSchema should succeed, but %s", err.Error())
+	}
+	expected := []string{
+		"C2 (Owner): Length of text should be 4 or less",
+		"B4 (Amount): Value should be 1000 or more",
+		"C4 (Owner): Value is required",
+		"A6 (Status): 'Pending' should be one of Open, Closed",
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("CheckSchema should find %d violations, but %d: %s", len(expected), len(violations), violations.Error())
+	}
+	for i, violation := range violations {
+		if violation.String() != expected[i] {
+			t.Errorf("violation %d should be '%s', but '%s'", i, expected[i], violation.String())
+		}
+	}
+}
+
+func TestCheckSchemaError(t *testing.T) {
+	file := createTableFile()
+	aRange := New(file.Sheet["Data"], "A1:C6")
+	if _, err := aRange.CheckSchema([]ColumnSchema{{Name: "Missing"}}); err == nil {
+		t.Errorf("CheckSchema should return error for missing column")
+	}
+	if _, err := aRange.CheckSchema([]ColumnSchema{{Name: "Owner", Pattern: "("}}); err == nil {
+		t.Errorf("CheckSchema should return error for invalid pattern")
+	}
+	if _, err := aRange.CheckSchema([]ColumnSchema{{Name: "Amount", Min: "1"}}); err == nil {
+		t.Errorf("CheckSchema should return error for string bound")
+	}
+	violations, _ := aRange.CheckSchema([]ColumnSchema{{Name: "Owner", Type: SchemaNumber}})
+	if len(violations) != 5 || violations[0].Message != "'alice' is not a number" {
+		t.Errorf("CheckSchema should check type, but %v", violations)
+	}
+}
@@ -0,0 +1,292 @@
+package xlsxrange
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// ConditionalRuleType is a kind of conditional formatting rule
+type ConditionalRuleType int
+
+const (
+	RuleCellValue  ConditionalRuleType = iota // Compares cell values with Formula1 (and Formula2) by Operator
+	RuleExpression                            // Applies Format when Formula1 is true. References are relative to the left top cell.
+	RuleColorScale                            // Colors cells by gradient of 2 or 3 Colors (minimum, (middle,) maximum)
+	RuleDataBar                               // Draws data bars by Colors[0]
+	RuleTopN                                  // Applies Format to top (or bottom) Rank items
+)
+
+// ConditionalOperator is a comparison operator of RuleCellValue
+type ConditionalOperator string
+
+const (
+	OperatorEqual              ConditionalOperator = "equal"
+	OperatorNotEqual           ConditionalOperator = "notEqual"
+	OperatorGreaterThan        ConditionalOperator = "greaterThan"
+	OperatorGreaterThanOrEqual ConditionalOperator = "greaterThanOrEqual"
+	OperatorLessThan           ConditionalOperator = "lessThan"
+	OperatorLessThanOrEqual    ConditionalOperator = "lessThanOrEqual"
+	OperatorBetween            ConditionalOperator = "between"
+	OperatorNotBetween         ConditionalOperator = "notBetween"
+)
+
+// ConditionalStyle is a differential style applied by conditional formatting rules.
+// Colors are ARGB like "FF9C0006". Empty colors are not changed.
+type ConditionalStyle struct {
+	FontColor string
+	FillColor string
+	Bold      bool
+	Italic    bool
+}
+
+// ConditionalRule is a rule of conditional formatting.
+//
+//	aRange.AddConditionalFormat(xlsxrange.ConditionalRule{
+//		Type:     xlsxrange.RuleCellValue,
+//		Operator: xlsxrange.OperatorGreaterThan,
+//		Formula1: "1000",
+//		Format:   &xlsxrange.ConditionalStyle{FontColor: "FF006100", FillColor: "FFC6EFCE"},
+//	})
+type ConditionalRule struct {
+	Type       ConditionalRuleType
+	Operator   ConditionalOperator // Used by RuleCellValue
+	Formula1   string              // Formula without "=". Strings should be quoted like "\"Open\"".
+	Formula2   string              // Upper bound of OperatorBetween and OperatorNotBetween
+	Colors     []string            // ARGB colors of RuleColorScale and RuleDataBar
+	Rank       int                 // Count (or percent) of RuleTopN. Default is 10.
+	Bottom     bool                // RuleTopN selects bottom items
+	Percent    bool                // Rank of RuleTopN is percent
+	Format     *ConditionalStyle   // Style of RuleCellValue, RuleExpression and RuleTopN
+	StopIfTrue bool
+}
+
+// ConditionalFormat is a set of conditional formatting rules bound to a range
+type ConditionalFormat struct {
+	Range *Range
+	Rules []ConditionalRule
+}
+
+// AddConditionalFormat attaches conditional formatting rules to selected range.
+//
+// Rules are kept by this package because tealeg/xlsx doesn't support conditional formatting.
+// Use xlsxrange.Save or xlsxrange.Write to write them into the file.
+func (r *Range) AddConditionalFormat(rules ...ConditionalRule) (*ConditionalFormat, error) {
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("Conditional format of %s needs at least one rule", r.Format(true))
+	}
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("Invalid conditional format rule for %s: %s", r.Format(true), err.Error())
+		}
+	}
+	format := &ConditionalFormat{Range: r, Rules: rules}
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext := sheetExtensionOf(r.Sheet)
+	ext.conditionalFormats = append(ext.conditionalFormats, format)
+	return format, nil
+}
+
+// ConditionalFormats returns conditional formats that are attached to cells in selected range
+// by AddConditionalFormat.
+func (r *Range) ConditionalFormats() []*ConditionalFormat {
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext := findSheetExtension(r.Sheet)
+	if ext == nil {
+		return nil
+	}
+	var result []*ConditionalFormat
+	for _, format := range ext.conditionalFormats {
+		if r.intersects(format.Range) {
+			result = append(result, format)
+		}
+	}
+	return result
+}
+
+// ClearConditionalFormats removes conditional formats that are attached to cells in selected range.
+func (r *Range) ClearConditionalFormats() {
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext := findSheetExtension(r.Sheet)
+	if ext == nil {
+		return
+	}
+	formats := ext.conditionalFormats[:0]
+	for _, format := range ext.conditionalFormats {
+		if !r.intersects(format.Range) {
+			formats = append(formats, format)
+		}
+	}
+	ext.conditionalFormats = formats
+}
+
+func (rule ConditionalRule) validate() error {
+	switch rule.Type {
+	case RuleCellValue:
+		if rule.Operator == "" {
+			return fmt.Errorf("Operator is required")
+		}
+		if rule.Formula1 == "" {
+			return fmt.Errorf("Formula1 is required")
+		}
+		if (rule.Operator == OperatorBetween || rule.Operator == OperatorNotBetween) && rule.Formula2 == "" {
+			return fmt.Errorf("Formula2 is required for %s", rule.Operator)
+		}
+	case RuleExpression:
+		if rule.Formula1 == "" {
+			return fmt.Errorf("Formula1 is required")
+		}
+	case RuleColorScale:
+		if len(rule.Colors) != 2 && len(rule.Colors) != 3 {
+			return fmt.Errorf("Color scale needs 2 or 3 colors, but %d", len(rule.Colors))
+		}
+	case RuleDataBar:
+		if len(rule.Colors) != 1 {
+			return fmt.Errorf("Data bar needs 1 color, but %d", len(rule.Colors))
+		}
+	case RuleTopN:
+		if rule.Rank < 0 {
+			return fmt.Errorf("Rank should be positive, but %d", rule.Rank)
+		}
+	default:
+		return fmt.Errorf("Unknown rule type %d", rule.Type)
+	}
+	return nil
+}
+
+type xlsxConditionalFormatting struct {
+	XMLName xml.Name     `xml:"conditionalFormatting"`
+	Sqref   string       `xml:"sqref,attr"`
+	Rules   []xlsxCfRule `xml:"cfRule"`
+}
+
+type xlsxCfRule struct {
+	Type       string          `xml:"type,attr"`
+	DxfID      *int            `xml:"dxfId,attr,omitempty"`
+	Priority   int             `xml:"priority,attr"`
+	StopIfTrue bool            `xml:"stopIfTrue,attr,omitempty"`
+	Operator   string          `xml:"operator,attr,omitempty"`
+	Rank       int             `xml:"rank,attr,omitempty"`
+	Bottom     bool            `xml:"bottom,attr,omitempty"`
+	Percent    bool            `xml:"percent,attr,omitempty"`
+	Formulas   []string        `xml:"formula"`
+	ColorScale *xlsxColorScale `xml:"colorScale"`
+	DataBar    *xlsxColorScale `xml:"dataBar"`
+}
+
+type xlsxColorScale struct {
+	Cfvos  []xlsxCfvo  `xml:"cfvo"`
+	Colors []xlsxColor `xml:"color"`
+}
+
+type xlsxCfvo struct {
+	Type string `xml:"type,attr"`
+	Val  string `xml:"val,attr,omitempty"`
+}
+
+type xlsxColor struct {
+	RGB string `xml:"rgb,attr"`
+}
+
+type xlsxDxf struct {
+	XMLName xml.Name     `xml:"dxf"`
+	Font    *xlsxDxfFont `xml:"font"`
+	Fill    *xlsxDxfFill `xml:"fill"`
+}
+
+type xlsxDxfFont struct {
+	Bold   *struct{}  `xml:"b"`
+	Italic *struct{}  `xml:"i"`
+	Color  *xlsxColor `xml:"color"`
+}
+
+type xlsxDxfFill struct {
+	PatternFill struct {
+		BgColor xlsxColor `xml:"bgColor"`
+	} `xml:"patternFill"`
+}
+
+// conditionalFormatting returns conditionalFormatting element of the format.
+// Differential styles are registered to the builder.
+func (b *packageBuilder) conditionalFormatting(format *ConditionalFormat) (string, error) {
+	element := xlsxConditionalFormatting{Sqref: format.Range.sqref()}
+	for _, rule := range format.Rules {
+		b.priority++
+		cfRule := xlsxCfRule{Priority: b.priority, StopIfTrue: rule.StopIfTrue}
+		withStyle := false
+		switch rule.Type {
+		case RuleCellValue:
+			cfRule.Type = "cellIs"
+			cfRule.Operator = string(rule.Operator)
+			cfRule.Formulas = []string{rule.Formula1}
+			if rule.Formula2 != "" {
+				cfRule.Formulas = append(cfRule.Formulas, rule.Formula2)
+			}
+			withStyle = true
+		case RuleExpression:
+			cfRule.Type = "expression"
+			cfRule.Formulas = []string{rule.Formula1}
+			withStyle = true
+		case RuleColorScale:
+			cfRule.Type = "colorScale"
+			scale := &xlsxColorScale{Cfvos: []xlsxCfvo{{Type: "min"}}}
+			if len(rule.Colors) == 3 {
+				scale.Cfvos = append(scale.Cfvos, xlsxCfvo{Type: "percentile", Val: "50"})
+			}
+			scale.Cfvos = append(scale.Cfvos, xlsxCfvo{Type: "max"})
+			for _, color := range rule.Colors {
+				scale.Colors = append(scale.Colors, xlsxColor{RGB: color})
+			}
+			cfRule.ColorScale = scale
+		case RuleDataBar:
+			cfRule.Type = "dataBar"
+			cfRule.DataBar = &xlsxColorScale{
+				Cfvos:  []xlsxCfvo{{Type: "min"}, {Type: "max"}},
+				Colors: []xlsxColor{{RGB: rule.Colors[0]}},
+			}
+		case RuleTopN:
+			cfRule.Type = "top10"
+			cfRule.Rank = rule.Rank
+			if cfRule.Rank == 0 {
+				cfRule.Rank = 10
+			}
+			cfRule.Bottom = rule.Bottom
+			cfRule.Percent = rule.Percent
+			withStyle = true
+		}
+		if withStyle && rule.Format != nil {
+			dxf, err := marshalXML(rule.Format.dxf())
+			if err != nil {
+				return "", err
+			}
+			id := len(b.dxfs)
+			b.dxfs = append(b.dxfs, dxf)
+			cfRule.DxfID = &id
+		}
+		element.Rules = append(element.Rules, cfRule)
+	}
+	return marshalXML(element)
+}
+
+func (s *ConditionalStyle) dxf() xlsxDxf {
+	var dxf xlsxDxf
+	if s.FontColor != "" || s.Bold || s.Italic {
+		dxf.Font = &xlsxDxfFont{}
+		if s.Bold {
+			dxf.Font.Bold = &struct{}{}
+		}
+		if s.Italic {
+			dxf.Font.Italic = &struct{}{}
+		}
+		if s.FontColor != "" {
+			dxf.Font.Color = &xlsxColor{RGB: s.FontColor}
+		}
+	}
+	if s.FillColor != "" {
+		dxf.Fill = &xlsxDxfFill{}
+		dxf.Fill.PatternFill.BgColor.RGB = s.FillColor
+	}
+	return dxf
+}
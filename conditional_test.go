@@ -0,0 +1,79 @@
+package xlsxrange
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddConditionalFormat(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 1"]
+	_, err := New(sheet, "B2:B10").AddConditionalFormat(
+		ConditionalRule{
+			Type:     RuleCellValue,
+			Operator: OperatorGreaterThan,
+			Formula1: "1000",
+			Format:   &ConditionalStyle{FontColor: "FF006100", FillColor: "FFC6EFCE"},
+		},
+		ConditionalRule{Type: RuleColorScale, Colors: []string{"FFF8696B", "FFFFEB84", "FF63BE7B"}},
+	)
+	if err != nil {
+		t.Fatalf("AddConditionalFormat should succeed, but %s", err.Error())
+	}
+	New(sheet, "D:D").AddConditionalFormat(ConditionalRule{Type: RuleTopN, Rank: 3, Bottom: true, Format: &ConditionalStyle{Bold: true}})
+	New(sheet, "E2").AddConditionalFormat(ConditionalRule{Type: RuleExpression, Formula1: `$A2="Open"`, Format: &ConditionalStyle{Italic: true}})
+
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	xml := parts["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(xml, `<conditionalFormatting sqref="B2:B10"><cfRule type="cellIs" dxfId="0" priority="1" operator="greaterThan"><formula>1000</formula></cfRule>`) {
+		t.Errorf("sheet should have cellIs rule, but %s", xml)
+	}
+	if !strings.Contains(xml, `<cfvo type="percentile" val="50"></cfvo>`) {
+		t.Errorf("3 colors scale should have percentile point, but %s", xml)
+	}
+	if !strings.Contains(xml, `<conditionalFormatting sqref="D:D"><cfRule type="top10" dxfId="1" priority="3" rank="3" bottom="true">`) {
+		t.Errorf("sheet should have top10 rule, but %s", xml)
+	}
+	if !strings.Contains(xml, `<formula>$A2=&#34;Open&#34;</formula>`) {
+		t.Errorf("formula should be escaped, but %s", xml)
+	}
+	if strings.Contains(parts["xl/worksheets/sheet2.xml"], "conditionalFormatting") {
+		t.Errorf("other sheet should not have conditional formatting")
+	}
+	styles := parts["xl/styles.xml"]
+	if !strings.Contains(styles, `<dxfs count="3"><dxf><font><color rgb="FF006100"></color></font><fill><patternFill><bgColor rgb="FFC6EFCE"></bgColor></patternFill></fill></dxf>`) {
+		t.Errorf("styles should have dxfs, but %s", styles)
+	}
+}
+
+func TestAddConditionalFormatError(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	if _, err := New(sheet, "A1").AddConditionalFormat(); err == nil {
+		t.Errorf("AddConditionalFormat should return error without rules")
+	}
+	if _, err := New(sheet, "A1").AddConditionalFormat(ConditionalRule{Type: RuleCellValue, Operator: OperatorBetween, Formula1: "1"}); err == nil {
+		t.Errorf("AddConditionalFormat should return error when between rule doesn't have Formula2")
+	}
+	if _, err := New(sheet, "A1").AddConditionalFormat(ConditionalRule{Type: RuleDataBar}); err == nil {
+		t.Errorf("AddConditionalFormat should return error when data bar doesn't have color")
+	}
+}
+
+func TestConditionalFormatsAndClear(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	New(sheet, "A1:B5").AddConditionalFormat(ConditionalRule{Type: RuleDataBar, Colors: []string{"FF638EC6"}})
+	New(sheet, "D1:D5").AddConditionalFormat(ConditionalRule{Type: RuleDataBar, Colors: []string{"FF638EC6"}})
+
+	if formats := New(sheet, "B3:C3").ConditionalFormats(); len(formats) != 1 || formats[0].Range.Format(false) != "A1:B5" {
+		t.Errorf("ConditionalFormats should return intersected format, but %d formats", len(formats))
+	}
+	New(sheet, "A1").ClearConditionalFormats()
+	if formats := New(sheet, "A:J").ConditionalFormats(); len(formats) != 1 {
+		t.Errorf("ClearConditionalFormats should remove intersected format, but %d formats remain", len(formats))
+	}
+}
@@ -0,0 +1,205 @@
+package xlsxrange
+
+import (
+	"github.com/tealeg/xlsx"
+)
+
+// PasteMode specifies which parts of cells are pasted by Range.CopyTo
+type PasteMode int
+
+const (
+	PasteAll      PasteMode = iota // Values, formulas, styles, merged cells, column widths and row heights
+	PasteValues                    // Values only. Formulas are replaced with their cached values.
+	PasteFormats                   // Styles, number formats, merged cells, column widths and row heights
+	PasteFormulas                  // Values and formulas without styles
+)
+
+// CopyOptions controls Range.CopyTo and Range.MoveTo.
+// nil is acceptable and it means zero value of this struct.
+type CopyOptions struct {
+	Mode       PasteMode
+	Transpose  bool // Swaps rows and columns
+	SkipBlanks bool // Doesn't overwrite destination cells by blank source cells
+}
+
+// CopyTo copies selected cells to left top corner of dest. dest can be in another sheet or another file.
+//
+// Relative references in formulas are shifted like Excel. It returns the range that was written.
+func (r *Range) CopyTo(dest *Range, opts *CopyOptions) *Range {
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+	rowCount, columnCount := r.size()
+	// take snapshot first because source and destination may overlap
+	snapshot := make([][]*xlsx.Cell, rowCount)
+	for row := range snapshot {
+		snapshot[row] = make([]*xlsx.Cell, columnCount)
+		for column := range snapshot[row] {
+			if src := r.cellAt(r.Row+row-1, r.Column+column-1); src != nil {
+				copied := *src
+				snapshot[row][column] = &copied
+			}
+		}
+	}
+	destRows, destColumns := rowCount, columnCount
+	if opts.Transpose {
+		destRows, destColumns = columnCount, rowCount
+	}
+	withFormats := opts.Mode == PasteAll || opts.Mode == PasteFormats
+
+	for row, cells := range snapshot {
+		for column, src := range cells {
+			if opts.SkipBlanks && cellKind(src) == kindBlank {
+				continue
+			}
+			destRow, destColumn := row, column
+			if opts.Transpose {
+				destRow, destColumn = column, row
+			}
+			absRow := dest.Row + destRow
+			absColumn := dest.Column + destColumn
+			dst := dest.Sheet.Cell(absRow-1, absColumn-1)
+			pasteCell(dst, src, opts.Mode, absRow-(r.Row+row), absColumn-(r.Column+column))
+			if withFormats && src != nil {
+				hMerge, vMerge := src.HMerge, src.VMerge
+				if opts.Transpose {
+					hMerge, vMerge = vMerge, hMerge
+				}
+				dst.HMerge = min(hMerge, destColumns-destColumn-1)
+				dst.VMerge = min(vMerge, destRows-destRow-1)
+			}
+		}
+	}
+
+	if withFormats && !opts.Transpose {
+		for column := 0; column < columnCount; column++ {
+			if src := findColumn(r.Sheet, r.Column+column); src != nil && src.Width != 0 {
+				sheetColumn(dest.Sheet, dest.Column+column).Width = src.Width
+			}
+		}
+		for row := 0; row < rowCount; row++ {
+			absRow := r.Row + row - 1
+			if absRow < len(r.Sheet.Rows) && r.Sheet.Rows[absRow] != nil && r.Sheet.Rows[absRow].Height != 0 {
+				dest.Sheet.Row(dest.Row + row - 1).SetHeight(r.Sheet.Rows[absRow].Height)
+			}
+		}
+	}
+	return New(dest.Sheet, dest.Row, dest.Column, destRows, destColumns)
+}
+
+// MoveTo moves selected cells to left top corner of dest.
+// Source cells that are not overwritten are cleared. It returns the range that was written.
+//
+// When dest is in the same sheet, references to the moved cells in formulas and data validations of
+// the workbook follow the cells, and references to the overwritten cells become #REF! like Excel.
+// References are not rewritten when cells are moved to another sheet.
+func (r *Range) MoveTo(dest *Range, opts *CopyOptions) *Range {
+	rowCount, columnCount := r.size()
+	if dest.Sheet == r.Sheet {
+		transpose := opts != nil && opts.Transpose
+		source := New(r.Sheet, r.Row, r.Column, rowCount, columnCount)
+		overwritten := New(dest.Sheet, dest.Row, dest.Column, rowCount, columnCount)
+		if transpose {
+			overwritten.NumRows, overwritten.NumColumns = columnCount, rowCount
+		}
+		// formulas of source cells are shifted by CopyTo
+		mapWorkbookFormulas(r.Sheet, source, func(formula string, local bool) string {
+			return moveCellsInFormula(formula, r.Sheet.Name, local, source, overwritten, func(row, column int) (int, int) {
+				if transpose {
+					return dest.Row + column - r.Column, dest.Column + row - r.Row
+				}
+				return dest.Row + row - r.Row, dest.Column + column - r.Column
+			})
+		})
+	}
+	written := r.CopyTo(dest, opts)
+	for row := 0; row < rowCount; row++ {
+		for column := 0; column < columnCount; column++ {
+			absRow := r.Row + row
+			absColumn := r.Column + column
+			if written.Sheet == r.Sheet && written.contains(absRow, absColumn) {
+				continue
+			}
+			if cell := r.cellAt(absRow-1, absColumn-1); cell != nil {
+				copyCell(cell, nil)
+			}
+		}
+	}
+	return written
+}
+
+// contains returns true if the cell (1 origin) is in selected range
+func (r *Range) contains(row, column int) bool {
+	rowCount, columnCount := r.size()
+	return r.Row <= row && row < r.Row+rowCount && r.Column <= column && column < r.Column+columnCount
+}
+
+// pasteCell pastes src into dst. Formulas are shifted by rows and columns.
+func pasteCell(dst, src *xlsx.Cell, mode PasteMode, rows, columns int) {
+	if src == nil {
+		src = &xlsx.Cell{}
+	}
+	switch mode {
+	case PasteValues:
+		setValueFrom(dst, src)
+	case PasteFormats:
+		dst.SetStyle(src.GetStyle())
+		dst.NumFmt = src.NumFmt
+	case PasteFormulas:
+		setValueFrom(dst, src)
+		setFormula(dst, src, shiftFormula(src.Formula(), rows, columns))
+	default:
+		copyCell(dst, src)
+		setFormula(dst, src, shiftFormula(src.Formula(), rows, columns))
+	}
+}
+
+// setValueFrom copies value and cell type of src into dst without formula, style and number format.
+func setValueFrom(dst, src *xlsx.Cell) {
+	value := src.Value
+	dst.SetFormula("")
+	switch src.Type() {
+	case xlsx.CellTypeNumeric:
+		dst.Value = value
+	case xlsx.CellTypeBool:
+		dst.SetBool(value == "1")
+	default:
+		dst.SetString(value)
+	}
+}
+
+// setFormula sets formula into dst keeping cell type of src
+func setFormula(dst, src *xlsx.Cell, formula string) {
+	if formula == "" {
+		return
+	}
+	value := dst.Value
+	if src.Type() == xlsx.CellTypeStringFormula || src.Type() == xlsx.CellTypeString {
+		dst.SetStringFormula(formula)
+	} else {
+		dst.SetFormula(formula)
+	}
+	dst.Value = value
+}
+
+// findColumn returns column setting of the column (1 origin). It returns nil if there is no setting.
+func findColumn(sheet *xlsx.Sheet, column int) *xlsx.Col {
+	if column < 1 || column > len(sheet.Cols) {
+		return nil
+	}
+	return sheet.Cols[column-1]
+}
+
+// sheetColumn returns column setting of the column (1 origin). It creates settings if needed.
+//
+// Min and Max of the setting are narrowed to the column to modify it independently.
+func sheetColumn(sheet *xlsx.Sheet, column int) *xlsx.Col {
+	for len(sheet.Cols) < column {
+		index := len(sheet.Cols) + 1
+		sheet.Cols = append(sheet.Cols, &xlsx.Col{Min: index, Max: index})
+	}
+	col := sheet.Cols[column-1]
+	col.Min = column
+	col.Max = column
+	return col
+}
@@ -0,0 +1,117 @@
+package xlsxrange
+
+import (
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestCopyTo(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[1].Cells[2].SetFormula("A1+$B$1+B$2")
+	sheet.Rows[0].Cells[0].Merge(1, 0)
+	style := xlsx.NewStyle()
+	style.Font.Bold = true
+	sheet.Rows[0].Cells[1].SetStyle(style)
+	sheet.Cols = []*xlsx.Col{{Min: 1, Max: 1, Width: 20}}
+	sheet.Rows[1].SetHeight(30)
+
+	dest := New(file.Sheet["Sheet 2"], "D5")
+	written := New(sheet, "A1:C2").CopyTo(dest, nil)
+	if written.Format(false) != "D5:F6" {
+		t.Errorf("written range should be 'D5:F6', but %s", written.Format(false))
+	}
+	if written.GetCellAt(1, 2).Formula() != "D5+$B$1+E$2" {
+		t.Errorf("formula should be shifted to 'D5+$B$1+E$2', but %s", written.GetCellAt(1, 2).Formula())
+	}
+	if written.GetCellAt(0, 0).HMerge != 1 {
+		t.Errorf("merge should be copied")
+	}
+	if !written.GetCellAt(0, 1).GetStyle().Font.Bold {
+		t.Errorf("style should be copied")
+	}
+	if findColumn(dest.Sheet, 4).Width != 20 {
+		t.Errorf("column width should be copied")
+	}
+	if dest.Sheet.Rows[5].Height != 30 {
+		t.Errorf("row height should be copied")
+	}
+}
+
+func TestCopyToWithOptions(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[0].Cells[1].SetString("")
+	sheet.Rows[1].Cells[0].SetFormula("B1*2")
+	sheet.Rows[1].Cells[0].Value = "4"
+
+	written := New(sheet, "A1:B2").CopyTo(New(sheet, "E1"), &CopyOptions{
+		Mode:       PasteValues,
+		Transpose:  true,
+		SkipBlanks: true,
+	})
+	if written.Format(false) != "E1:F2" {
+		t.Errorf("written range should be 'E1:F2', but %s", written.Format(false))
+	}
+	if written.GetCellAt(0, 1).Value != "4" || written.GetCellAt(0, 1).Formula() != "" {
+		t.Errorf("F1 should be value '4' without formula, but '%s' '%s'", written.GetCellAt(0, 1).Value, written.GetCellAt(0, 1).Formula())
+	}
+	if written.GetCellAt(1, 0).Value != "E2" {
+		t.Errorf("E2 should be kept by SkipBlanks, but '%s'", written.GetCellAt(1, 0).Value)
+	}
+}
+
+func TestMoveTo(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+
+	written := New(sheet, "A1:B2").MoveTo(New(sheet, "B2"), nil)
+	if written.GetCellAt(0, 0).Value != "A1" || written.GetCellAt(1, 1).Value != "B2" {
+		t.Errorf("cells should be moved")
+	}
+	if sheet.Rows[0].Cells[0].Value != "" || sheet.Rows[0].Cells[1].Value != "" || sheet.Rows[1].Cells[0].Value != "" {
+		t.Errorf("source cells should be cleared")
+	}
+}
+
+func TestMoveToRewritesReferences(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[9].Cells[0].SetFormula("SUM(A1:B2)+A1+$B$2+C3+D4+SUM(A1:D4)")
+	sheet.Rows[1].Cells[1].SetFormula("A1*2")
+	file.Sheet["Sheet 2"].Rows[0].Cells[0].SetFormula("'Sheet 1'!A2+A2")
+
+	New(sheet, "A1:B2").MoveTo(New(sheet, "B3"), nil)
+	expected := "SUM(B3:C4)+B3+$C$4+#REF!+D4+SUM(A1:D4)"
+	if formula := sheet.Rows[9].Cells[0].Formula(); formula != expected {
+		t.Errorf("formula should be '%s', but '%s'", expected, formula)
+	}
+	if formula := sheet.Rows[3].Cells[2].Formula(); formula != "B3*2" {
+		t.Errorf("formula of moved cell should be 'B3*2', but '%s'", formula)
+	}
+	if formula := file.Sheet["Sheet 2"].Rows[0].Cells[0].Formula(); formula != "'Sheet 1'!B4+A2" {
+		t.Errorf("formula in other sheet should be \"'Sheet 1'!B4+A2\", but '%s'", formula)
+	}
+
+	New(sheet, "B3").MoveTo(New(file.Sheet["Sheet 3"], "A1"), nil)
+	if formula := sheet.Rows[9].Cells[0].Formula(); formula != expected {
+		t.Errorf("formula should not be rewritten when cells are moved to another sheet, but '%s'", formula)
+	}
+}
+
+func TestShiftFormula(t *testing.T) {
+	cases := [][]string{
+		{`SUM(A1:B2)`, `SUM(B3:C4)`},
+		{`LOG10(A1)&"A1"`, `LOG10(B3)&"A1"`},
+		{`'My Sheet'!$A1`, `'My Sheet'!$A3`},
+	}
+	for _, c := range cases {
+		if shiftFormula(c[0], 2, 1) != c[1] {
+			t.Errorf("shiftFormula(%s) should be '%s', but '%s'", c[0], c[1], shiftFormula(c[0], 2, 1))
+		}
+	}
+	if shiftFormula("A1", -1, 0) != "#REF!" {
+		t.Errorf("reference out of sheet should be #REF!, but %s", shiftFormula("A1", -1, 0))
+	}
+}
@@ -0,0 +1,142 @@
+package xlsxrange
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tealeg/xlsx"
+)
+
+// typedValue returns cell value as Go value.
+//
+// Blank cells are nil, numeric cells are float64 (time.Time if it has date format),
+// boolean cells are bool and others are string.
+func typedValue(cell *xlsx.Cell, date1904 bool) interface{} {
+	switch cellKind(cell) {
+	case kindBlank:
+		return nil
+	case kindNumber:
+		if cell.IsTime() {
+			t, err := cell.GetTime(date1904)
+			if err == nil {
+				return t
+			}
+		}
+		value, _ := strconv.ParseFloat(cell.Value, 64)
+		return value
+	case kindBool:
+		return cell.Value == "1"
+	}
+	return cell.Value
+}
+
+// criterion is a compiled Excel style condition like ">1000", "<>Closed" or "A*"
+type criterion func(cell *xlsx.Cell) bool
+
+// compileCriterion parses Excel style criteria text.
+//
+// If prefixMatch is true, text criteria without operator match cells beginning with the text
+// like AdvancedFilter. Otherwise they match whole text like COUNTIF.
+func compileCriterion(text string, prefixMatch bool) criterion {
+	operator := ""
+	for _, candidate := range []string{"<>", ">=", "<=", "=", ">", "<"} {
+		if strings.HasPrefix(text, candidate) {
+			operator = candidate
+			text = text[len(candidate):]
+			break
+		}
+	}
+	if operator == "" && text == "" {
+		return func(cell *xlsx.Cell) bool { return true }
+	}
+	if text == "" {
+		switch operator {
+		case "=":
+			return func(cell *xlsx.Cell) bool { return cellKind(cell) == kindBlank }
+		case "<>":
+			return func(cell *xlsx.Cell) bool { return cellKind(cell) != kindBlank }
+		}
+	}
+
+	if number, err := strconv.ParseFloat(text, 64); err == nil {
+		return func(cell *xlsx.Cell) bool {
+			if cellKind(cell) != kindNumber {
+				return operator == "<>"
+			}
+			value, _ := strconv.ParseFloat(cell.Value, 64)
+			return compareResult(operator, compareFloat(value, number))
+		}
+	}
+
+	lowerText := strings.ToLower(text)
+	if operator == "" || operator == "=" || operator == "<>" {
+		pattern := wildcardToRegexp(text, operator == "" && prefixMatch)
+		return func(cell *xlsx.Cell) bool {
+			matched := cell != nil && pattern.MatchString(cell.Value)
+			if operator == "<>" {
+				return !matched
+			}
+			return matched
+		}
+	}
+	return func(cell *xlsx.Cell) bool {
+		if cellKind(cell) != kindText {
+			return false
+		}
+		return compareResult(operator, strings.Compare(strings.ToLower(cell.Value), lowerText))
+	}
+}
+
+func compareFloat(a, b float64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func compareResult(operator string, result int) bool {
+	switch operator {
+	case "<>":
+		return result != 0
+	case ">=":
+		return result >= 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case "<":
+		return result < 0
+	}
+	return result == 0
+}
+
+// wildcardToRegexp converts Excel wildcard (*, ? and ~ escape) into case insensitive regular expression
+func wildcardToRegexp(text string, prefixMatch bool) *regexp.Regexp {
+	var buffer strings.Builder
+	buffer.WriteString("(?is)^")
+	escaped := false
+	for _, c := range text {
+		if escaped {
+			buffer.WriteString(regexp.QuoteMeta(string(c)))
+			escaped = false
+			continue
+		}
+		switch c {
+		case '~':
+			escaped = true
+		case '*':
+			buffer.WriteString(".*")
+		case '?':
+			buffer.WriteString(".")
+		default:
+			buffer.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if !prefixMatch {
+		buffer.WriteString("$")
+	}
+	return regexp.MustCompile(buffer.String())
+}
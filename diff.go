@@ -0,0 +1,285 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/tealeg/xlsx"
+)
+
+// ChangeType is a kind of cell change
+type ChangeType int
+
+const (
+	CellAdded   ChangeType = iota // Cell is blank in old range and has value in new range
+	CellRemoved                   // Cell has value in old range and is blank (or missing) in new range
+	CellChanged                   // Cell value or formula is changed
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case CellAdded:
+		return "+"
+	case CellRemoved:
+		return "-"
+	}
+	return "~"
+}
+
+// CellChange is a difference of a cell
+type CellChange struct {
+	Type       ChangeType
+	OldAddress string // A1 address in old range. Empty for added cells in added rows.
+	NewAddress string // A1 address in new range. Empty for removed cells in removed rows.
+	OldValue   string
+	NewValue   string
+}
+
+// DiffOptions controls Diff.
+// nil is acceptable and it means zero value of this struct.
+type DiffOptions struct {
+	// KeyColumn is a header name of key column. If it is specified, both ranges are treated as header-row
+	// ranges and rows are aligned by key values and columns are aligned by header names.
+	// Otherwise cells are compared at same relative positions.
+	KeyColumn string
+}
+
+// DiffResult is a result of Diff
+type DiffResult struct {
+	Old     *Range
+	New     *Range
+	Changes []CellChange
+}
+
+// Diff compares two ranges and reports added, removed and changed cells.
+func Diff(a, b *Range, opts *DiffOptions) (*DiffResult, error) {
+	if opts == nil {
+		opts = &DiffOptions{}
+	}
+	result := &DiffResult{Old: a, New: b}
+	if opts.KeyColumn == "" {
+		result.diffCells()
+		return result, nil
+	}
+	return result, result.diffByKey(opts.KeyColumn)
+}
+
+// HasChanges returns true if there are any changes
+func (d *DiffResult) HasChanges() bool {
+	return len(d.Changes) > 0
+}
+
+func (d *DiffResult) diffCells() {
+	oldRows, oldColumns := d.Old.size()
+	newRows, newColumns := d.New.size()
+	rowCount := max(oldRows, newRows)
+	columnCount := max(oldColumns, newColumns)
+	for row := 0; row < rowCount; row++ {
+		for column := 0; column < columnCount; column++ {
+			var oldCell, newCell *xlsx.Cell
+			oldAddress := ""
+			newAddress := ""
+			if row < oldRows && column < oldColumns {
+				oldCell = d.Old.cellAt(d.Old.Row+row-1, d.Old.Column+column-1)
+				oldAddress = cellName(d.Old.Row+row, d.Old.Column+column)
+			}
+			if row < newRows && column < newColumns {
+				newCell = d.New.cellAt(d.New.Row+row-1, d.New.Column+column-1)
+				newAddress = cellName(d.New.Row+row, d.New.Column+column)
+			}
+			d.compare(oldCell, newCell, oldAddress, newAddress)
+		}
+	}
+}
+
+func (d *DiffResult) diffByKey(keyColumn string) error {
+	oldHeader := d.Old.header()
+	newHeader := d.New.header()
+	oldKey := indexOf(oldHeader, keyColumn)
+	newKey := indexOf(newHeader, keyColumn)
+	if oldKey == -1 {
+		return fmt.Errorf("Key column '%s' is not found in header of %s", keyColumn, d.Old.Format(true))
+	}
+	if newKey == -1 {
+		return fmt.Errorf("Key column '%s' is not found in header of %s", keyColumn, d.New.Format(true))
+	}
+	oldRows := d.Old.keyedRows(oldKey)
+	newRows := d.New.keyedRows(newKey)
+
+	// columns in new header order, then removed columns
+	type columnPair struct{ old, new int }
+	var columns []columnPair
+	for i, name := range newHeader {
+		columns = append(columns, columnPair{old: indexOf(oldHeader, name), new: i})
+	}
+	for i, name := range oldHeader {
+		if indexOf(newHeader, name) == -1 {
+			columns = append(columns, columnPair{old: i, new: -1})
+		}
+	}
+	oldAt := func(rowNumber, column int) (*xlsx.Cell, string) {
+		if rowNumber == 0 || column == -1 {
+			return nil, ""
+		}
+		return d.Old.cellAt(rowNumber-1, d.Old.Column+column-1), cellName(rowNumber, d.Old.Column+column)
+	}
+	newAt := func(rowNumber, column int) (*xlsx.Cell, string) {
+		if rowNumber == 0 || column == -1 {
+			return nil, ""
+		}
+		return d.New.cellAt(rowNumber-1, d.New.Column+column-1), cellName(rowNumber, d.New.Column+column)
+	}
+
+	for _, key := range newRows.keys {
+		newRowNumber := newRows.rows[key]
+		oldRowNumber := oldRows.rows[key]
+		for _, pair := range columns {
+			oldCell, oldAddress := oldAt(oldRowNumber, pair.old)
+			newCell, newAddress := newAt(newRowNumber, pair.new)
+			d.compare(oldCell, newCell, oldAddress, newAddress)
+		}
+	}
+	for _, key := range oldRows.keys {
+		if _, ok := newRows.rows[key]; ok {
+			continue
+		}
+		for _, pair := range columns {
+			oldCell, oldAddress := oldAt(oldRows.rows[key], pair.old)
+			d.compare(oldCell, nil, oldAddress, "")
+		}
+	}
+	return nil
+}
+
+type keyedRows struct {
+	keys []string
+	rows map[string]int // key -> row number
+}
+
+// keyedRows returns data row numbers by key values. If keys are duplicated, the first row is used.
+func (r *Range) keyedRows(keyColumn int) keyedRows {
+	result := keyedRows{rows: make(map[string]int)}
+	rows := r.Rows(nil)
+	for rows.Next() {
+		if rows.RowNumber() == r.Row {
+			continue
+		}
+		cell := rows.Row()[keyColumn]
+		if cellKind(cell) == kindBlank {
+			continue
+		}
+		if _, ok := result.rows[cell.Value]; !ok {
+			result.keys = append(result.keys, cell.Value)
+			result.rows[cell.Value] = rows.RowNumber()
+		}
+	}
+	return result
+}
+
+func (d *DiffResult) compare(oldCell, newCell *xlsx.Cell, oldAddress, newAddress string) {
+	oldBlank := cellKind(oldCell) == kindBlank
+	newBlank := cellKind(newCell) == kindBlank
+	change := CellChange{
+		OldAddress: oldAddress,
+		NewAddress: newAddress,
+		OldValue:   cellContent(oldCell),
+		NewValue:   cellContent(newCell),
+	}
+	switch {
+	case oldBlank && newBlank:
+		return
+	case oldBlank:
+		change.Type = CellAdded
+	case newBlank:
+		change.Type = CellRemoved
+	case change.OldValue == change.NewValue:
+		return
+	default:
+		change.Type = CellChanged
+	}
+	d.Changes = append(d.Changes, change)
+}
+
+// cellContent returns formula (with "=") or value of the cell
+func cellContent(cell *xlsx.Cell) string {
+	if cell == nil {
+		return ""
+	}
+	if cell.Formula() != "" {
+		return "=" + cell.Formula()
+	}
+	return cell.Value
+}
+
+// WriteReport writes unified text report.
+//
+//	--- Sheet1!A1:C6
+//	+++ Sheet2!A1:C6
+//	~ B3 "1500" -> B3 "1600"
+//	+ C7 "new"
+//	- A4 "old"
+func (d *DiffResult) WriteReport(w io.Writer) error {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "--- %s\n+++ %s\n", d.Old.Format(true), d.New.Format(true))
+	for _, change := range d.Changes {
+		switch change.Type {
+		case CellAdded:
+			fmt.Fprintf(&buffer, "+ %s %q\n", change.NewAddress, change.NewValue)
+		case CellRemoved:
+			fmt.Fprintf(&buffer, "- %s %q\n", change.OldAddress, change.OldValue)
+		default:
+			fmt.Fprintf(&buffer, "~ %s %q -> %s %q\n", change.OldAddress, change.OldValue, change.NewAddress, change.NewValue)
+		}
+	}
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+// Report returns unified text report. See WriteReport.
+func (d *DiffResult) Report() string {
+	var buffer bytes.Buffer
+	d.WriteReport(&buffer)
+	return buffer.String()
+}
+
+// Highlight colors (ARGB) used by DiffResult.HighlightTo
+var (
+	HighlightAdded   = xlsx.RGB_Light_Green
+	HighlightChanged = "FFFFEB9C"
+)
+
+// HighlightTo copies new range into left top corner of dest and fills added and changed cells.
+// Removed cells are not shown. It returns the range that was written.
+func (d *DiffResult) HighlightTo(dest *Range) *Range {
+	rowCount, columnCount := d.New.size()
+	for row := 0; row < rowCount; row++ {
+		for column := 0; column < columnCount; column++ {
+			src := d.New.cellAt(d.New.Row+row-1, d.New.Column+column-1)
+			copyCell(dest.Sheet.Cell(dest.Row+row-1, dest.Column+column-1), src)
+		}
+	}
+	styles := make(map[[2]interface{}]*xlsx.Style)
+	for _, change := range d.Changes {
+		if change.NewAddress == "" || change.Type == CellRemoved {
+			continue
+		}
+		_, position, _ := ParseA1Notation(change.NewAddress)
+		cell := dest.Sheet.Cell(dest.Row+position[0]-d.New.Row-1, dest.Column+position[1]-d.New.Column-1)
+		color := HighlightChanged
+		if change.Type == CellAdded {
+			color = HighlightAdded
+		}
+		original := cell.GetStyle()
+		style, ok := styles[[2]interface{}{original, color}]
+		if !ok {
+			copied := *original
+			copied.Fill = *xlsx.NewFill(xlsx.Solid_Cell_Fill, color, color)
+			copied.ApplyFill = true
+			style = &copied
+			styles[[2]interface{}{original, color}] = style
+		}
+		cell.SetStyle(style)
+	}
+	return New(dest.Sheet, dest.Row, dest.Column, rowCount, columnCount)
+}
@@ -0,0 +1,77 @@
+package xlsxrange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestDiff(t *testing.T) {
+	file := createFile()
+	oldRange := New(file.Sheet["Sheet 1"], "A1:C3")
+	newRange := New(file.Sheet["Sheet 2"], "A1:C3")
+	file.Sheet["Sheet 2"].Rows[1].Cells[1].SetString("changed")
+	file.Sheet["Sheet 2"].Rows[2].Cells[2].SetString("")
+	file.Sheet["Sheet 1"].Rows[0].Cells[0].SetString("")
+
+	result, err := Diff(oldRange, newRange, nil)
+	if err != nil {
+		t.Errorf("Diff should not return error, but %s", err)
+	}
+	if len(result.Changes) != 3 {
+		t.Errorf("Diff should find 3 changes, but %d", len(result.Changes))
+	}
+	expected := strings.Join([]string{
+		"--- Sheet 1!A1:C3",
+		"+++ Sheet 2!A1:C3",
+		`+ A1 "A1"`,
+		`~ B2 "B2" -> B2 "changed"`,
+		`- C3 "C3"`,
+		"",
+	}, "\n")
+	if result.Report() != expected {
+		t.Errorf("Report should be\n%s\nbut\n%s", expected, result.Report())
+	}
+}
+
+func TestDiffByKey(t *testing.T) {
+	file := xlsx.NewFile()
+	oldSheet, _ := file.AddSheet("Old")
+	newSheet, _ := file.AddSheet("New")
+	New(oldSheet, "A1").ReadCSV(strings.NewReader("ID,Name,Price\n1,apple,100\n2,orange,80\n3,grape,300\n"), nil)
+	New(newSheet, "A1").ReadCSV(strings.NewReader("ID,Price,Name\n3,300,grape\n1,120,apple\n4,50,lemon\n"), nil)
+
+	result, err := Diff(New(oldSheet, "A1:C4"), New(newSheet, "A1:C4"), &DiffOptions{KeyColumn: "ID"})
+	if err != nil {
+		t.Errorf("Diff should not return error, but %s", err)
+	}
+	var changed, added, removed int
+	for _, change := range result.Changes {
+		switch change.Type {
+		case CellChanged:
+			changed++
+			if change.OldAddress != "C2" || change.NewAddress != "B3" {
+				t.Errorf("changed cell should be C2 -> B3, but %s -> %s", change.OldAddress, change.NewAddress)
+			}
+		case CellAdded:
+			added++
+		case CellRemoved:
+			removed++
+		}
+	}
+	if changed != 1 || added != 3 || removed != 3 {
+		t.Errorf("changes should be 1 changed, 3 added, 3 removed, but %d, %d, %d", changed, added, removed)
+	}
+
+	highlighted := result.HighlightTo(New(newSheet, "E1"))
+	if highlighted.GetCellAt(2, 1).GetStyle().Fill.FgColor != HighlightChanged {
+		t.Errorf("F3 should be highlighted as changed")
+	}
+	if highlighted.GetCellAt(3, 0).GetStyle().Fill.FgColor != HighlightAdded {
+		t.Errorf("E4 should be highlighted as added")
+	}
+	if highlighted.GetCellAt(1, 0).GetStyle().Fill.PatternType == xlsx.Solid_Cell_Fill {
+		t.Errorf("E2 should not be highlighted")
+	}
+}
@@ -0,0 +1,242 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/tealeg/xlsx"
+)
+
+// JSONLayout specifies the shape of JSON output of Range.WriteJSON
+type JSONLayout int
+
+const (
+	JSONArrays  JSONLayout = iota // Array of arrays: [["a", "b"], ["c", "d"]]
+	JSONObjects                   // Array of objects. Header row values are used as keys.
+)
+
+// ExportOptions controls how cell values are exported.
+// nil is acceptable and it means zero value of this struct.
+type ExportOptions struct {
+	Formatted  bool       // Uses formatted value (FormattedValue()) instead of raw value
+	DateFormat string     // Go time layout for date cells. Empty means no conversion.
+	TrimEmpty  bool       // Trims trailing empty rows and columns
+	Comma      rune       // Field delimiter of CSV. Default is ','
+	Layout     JSONLayout // JSON layout
+	Indent     string     // Indent string of JSON. Empty means compact output.
+
+	SkipHidden    bool // Skips hidden rows and columns
+	SkipCollapsed bool // Skips rows and columns hidden by collapsed outline groups
+}
+
+// WriteCSV writes selected cells as CSV
+func (r *Range) WriteCSV(w io.Writer, opts *ExportOptions) error {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	writer := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		writer.Comma = opts.Comma
+	}
+	if err := writer.WriteAll(r.textMatrix(opts)); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// WriteTSV writes selected cells as TSV
+func (r *Range) WriteTSV(w io.Writer, opts *ExportOptions) error {
+	tsvOpts := ExportOptions{}
+	if opts != nil {
+		tsvOpts = *opts
+	}
+	tsvOpts.Comma = '\t'
+	return r.WriteCSV(w, &tsvOpts)
+}
+
+// WriteJSON writes selected cells as JSON
+//
+// If opts.Formatted is false, numeric and boolean cells are written as JSON numbers and booleans.
+// If opts.Layout is JSONObjects, first row is treated as header row and keys are written in header order.
+// Header names should be unique.
+func (r *Range) WriteJSON(w io.Writer, opts *ExportOptions) error {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	values := r.valueMatrix(opts)
+	var result interface{}
+	if opts.Layout == JSONObjects {
+		objects := make([]jsonObject, 0)
+		if len(values) > 0 {
+			header := make([]string, len(values[0]))
+			for i, key := range values[0] {
+				header[i] = textValue(key)
+				if indexOf(header[:i], header[i]) != -1 {
+					return fmt.Errorf("Header name '%s' of %s is duplicated", header[i], r.Format(true))
+				}
+			}
+			for _, row := range values[1:] {
+				objects = append(objects, jsonObject{keys: header, values: row})
+			}
+		}
+		result = objects
+	} else {
+		result = values
+	}
+	encoder := json.NewEncoder(w)
+	if opts.Indent != "" {
+		encoder.SetIndent("", opts.Indent)
+	}
+	return encoder.Encode(result)
+}
+
+// textMatrix returns selected cell values as strings
+func (r *Range) textMatrix(opts *ExportOptions) [][]string {
+	result := [][]string{}
+	for _, cells := range r.GetVisibleCells(opts.iterateOptions()) {
+		texts := make([]string, len(cells))
+		for column, cell := range cells {
+			texts[column] = r.cellText(cell, opts)
+		}
+		result = append(result, texts)
+	}
+	if opts.TrimEmpty {
+		result = trimMatrix(result, func(value string) bool { return value == "" })
+	}
+	return result
+}
+
+func (opts *ExportOptions) iterateOptions() *IterateOptions {
+	return &IterateOptions{
+		SkipHidden:        opts.SkipHidden,
+		SkipHiddenColumns: opts.SkipHidden,
+		SkipCollapsed:     opts.SkipCollapsed,
+	}
+}
+
+// valueMatrix returns selected cell values as JSON friendly values
+func (r *Range) valueMatrix(opts *ExportOptions) [][]interface{} {
+	result := [][]interface{}{}
+	for _, cells := range r.GetVisibleCells(opts.iterateOptions()) {
+		values := make([]interface{}, len(cells))
+		for column, cell := range cells {
+			values[column] = r.cellValue(cell, opts)
+		}
+		result = append(result, values)
+	}
+	if opts.TrimEmpty {
+		result = trimMatrix(result, func(value interface{}) bool { return value == "" })
+	}
+	return result
+}
+
+// jsonObject is a JSON object that keeps order of keys
+type jsonObject struct {
+	keys   []string
+	values []interface{}
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// cellText returns string representation of a cell
+func (r *Range) cellText(cell *xlsx.Cell, opts *ExportOptions) string {
+	if cell == nil {
+		return ""
+	}
+	if opts.DateFormat != "" && cell.Type() == xlsx.CellTypeNumeric && cell.Value != "" && cell.IsTime() {
+		t, err := cell.GetTime(r.File != nil && r.File.Date1904)
+		if err == nil {
+			return t.Format(opts.DateFormat)
+		}
+	}
+	if opts.Formatted {
+		value, err := cell.FormattedValue()
+		if err == nil {
+			return value
+		}
+	}
+	return cell.Value
+}
+
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// cellValue returns typed value of a cell. Numbers that are not finite or not written in JSON number syntax
+// are returned as text.
+func (r *Range) cellValue(cell *xlsx.Cell, opts *ExportOptions) interface{} {
+	text := r.cellText(cell, opts)
+	if cell == nil || opts.Formatted || text != cell.Value {
+		return text
+	}
+	switch cell.Type() {
+	case xlsx.CellTypeNumeric:
+		number, err := strconv.ParseFloat(cell.Value, 64)
+		if err == nil && !math.IsInf(number, 0) && !math.IsNaN(number) && jsonNumberPattern.MatchString(cell.Value) {
+			return json.Number(cell.Value)
+		}
+	case xlsx.CellTypeBool:
+		return cell.Value == "1"
+	}
+	return text
+}
+
+func textValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// trimMatrix removes trailing empty rows and columns
+func trimMatrix[T any](matrix [][]T, isEmpty func(T) bool) [][]T {
+	rowCount := 0
+	columnCount := 0
+	for row, values := range matrix {
+		for column, value := range values {
+			if !isEmpty(value) {
+				if row+1 > rowCount {
+					rowCount = row + 1
+				}
+				if column+1 > columnCount {
+					columnCount = column + 1
+				}
+			}
+		}
+	}
+	matrix = matrix[:rowCount]
+	for row := range matrix {
+		matrix[row] = matrix[row][:columnCount]
+	}
+	return matrix
+}
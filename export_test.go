@@ -0,0 +1,160 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "B2:C3")
+
+	var buffer bytes.Buffer
+	if err := aRange.WriteCSV(&buffer, nil); err != nil {
+		t.Fatalf("WriteCSV should succeed, but %s", err.Error())
+	}
+	expected := "B2,C2\nB3,C3\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteCSV should write '%s', but '%s'", expected, buffer.String())
+	}
+}
+
+func TestWriteTSV(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "B2:C3")
+
+	var buffer bytes.Buffer
+	if err := aRange.WriteTSV(&buffer, nil); err != nil {
+		t.Fatalf("WriteTSV should succeed, but %s", err.Error())
+	}
+	expected := "B2\tC2\nB3\tC3\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteTSV should write '%s', but '%s'", expected, buffer.String())
+	}
+}
+
+func TestWriteCSVWithTrimEmpty(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[2].Cells[2].SetString("")
+	sheet.Rows[1].Cells[2].SetString("")
+	sheet.Rows[2].Cells[1].SetString("")
+	aRange := New(sheet, "B2:C3")
+
+	var buffer bytes.Buffer
+	if err := aRange.WriteCSV(&buffer, &ExportOptions{TrimEmpty: true}); err != nil {
+		t.Fatalf("WriteCSV should succeed, but %s", err.Error())
+	}
+	expected := "B2\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteCSV should write '%s', but '%s'", expected, buffer.String())
+	}
+}
+
+func TestWriteCSVWithSkipHidden(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	New(sheet, "3:3").HideRows()
+	New(sheet, "C:C").HideColumns()
+	aRange := New(sheet, "B2:D4")
+
+	var buffer bytes.Buffer
+	if err := aRange.WriteCSV(&buffer, &ExportOptions{SkipHidden: true}); err != nil {
+		t.Fatalf("WriteCSV should succeed, but %s", err.Error())
+	}
+	expected := "B2,D2\nB4,D4\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteCSV should write '%s', but '%s'", expected, buffer.String())
+	}
+}
+
+func TestWriteCSVWithDateFormat(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[0].Cells[0].SetDate(time.Date(2016, 4, 1, 0, 0, 0, 0, time.UTC))
+	aRange := New(sheet, "A1")
+
+	var buffer bytes.Buffer
+	if err := aRange.WriteCSV(&buffer, &ExportOptions{DateFormat: "2006/01/02"}); err != nil {
+		t.Fatalf("WriteCSV should succeed, but %s", err.Error())
+	}
+	expected := "2016/04/01\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteCSV should write '%s', but '%s'", expected, buffer.String())
+	}
+}
+
+func TestWriteJSONArrays(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[2].Cells[2].SetInt(10)
+	sheet.Rows[2].Cells[1].SetBool(true)
+	aRange := New(sheet, "B2:C3")
+
+	var buffer bytes.Buffer
+	if err := aRange.WriteJSON(&buffer, nil); err != nil {
+		t.Fatalf("WriteJSON should succeed, but %s", err.Error())
+	}
+	expected := `[["B2","C2"],[true,10]]` + "\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteJSON should write '%s', but '%s'", expected, buffer.String())
+	}
+}
+
+func TestWriteJSONNonFiniteNumbers(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[0].Cells[0].SetFloat(math.NaN())
+	sheet.Rows[0].Cells[1].SetFloat(math.Inf(1))
+	sheet.Rows[0].Cells[2].SetInt(7)
+	sheet.Rows[0].Cells[2].Value = "007"
+	sheet.Rows[0].Cells[3].SetInt(-12)
+	aRange := New(sheet, "A1:D1")
+
+	var buffer bytes.Buffer
+	if err := aRange.WriteJSON(&buffer, nil); err != nil {
+		t.Fatalf("WriteJSON should succeed, but %s", err.Error())
+	}
+	expected := `[["NaN","+Inf","007",-12]]` + "\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteJSON should write '%s', but '%s'", expected, buffer.String())
+	}
+}
+
+func TestWriteJSONObjects(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "B2:C3")
+
+	var buffer bytes.Buffer
+	if err := aRange.WriteJSON(&buffer, &ExportOptions{Layout: JSONObjects}); err != nil {
+		t.Fatalf("WriteJSON should succeed, but %s", err.Error())
+	}
+	expected := `[{"B2":"B3","C2":"C3"}]` + "\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteJSON should write '%s', but '%s'", expected, buffer.String())
+	}
+}
+
+func TestWriteJSONObjectsKeepsHeaderOrder(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[1].Cells[1].SetString("Zip")
+	sheet.Rows[1].Cells[2].SetString("Area")
+	aRange := New(sheet, "B2:C3")
+
+	var buffer bytes.Buffer
+	if err := aRange.WriteJSON(&buffer, &ExportOptions{Layout: JSONObjects}); err != nil {
+		t.Fatalf("WriteJSON should succeed, but %s", err.Error())
+	}
+	expected := `[{"Zip":"B3","Area":"C3"}]` + "\n"
+	if buffer.String() != expected {
+		t.Errorf("WriteJSON should write keys in header order '%s', but '%s'", expected, buffer.String())
+	}
+
+	sheet.Rows[1].Cells[2].SetString("Zip")
+	if err := aRange.WriteJSON(&buffer, &ExportOptions{Layout: JSONObjects}); err == nil {
+		t.Errorf("WriteJSON should return error for duplicated header names")
+	}
+}
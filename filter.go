@@ -0,0 +1,190 @@
+package xlsxrange
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tealeg/xlsx"
+)
+
+// Record is a data row of a range with header row
+type Record struct {
+	RowNumber int           // Row number (1 origin) in the sheet
+	Header    []string      // Header names
+	Values    []interface{} // Typed values (nil, float64, time.Time, bool or string)
+	Cells     []*xlsx.Cell  // Source cells
+}
+
+// Get returns typed value of the column. It returns nil if the column is missing.
+func (rec Record) Get(name string) interface{} {
+	for i, header := range rec.Header {
+		if header == name {
+			return rec.Values[i]
+		}
+	}
+	return nil
+}
+
+// String returns value of the column as string
+func (rec Record) String(name string) string {
+	value := rec.Get(name)
+	if value == nil {
+		return ""
+	}
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Float returns value of the column as float64. ok is false if the value is not a number.
+func (rec Record) Float(name string) (value float64, ok bool) {
+	value, ok = rec.Get(name).(float64)
+	return
+}
+
+// View is a set of rows selected from a range with header row
+type View struct {
+	Source *Range // Source range including header row
+	Header []string
+	Rows   []int // Row numbers (1 origin) of selected rows in the sheet
+}
+
+// Filter returns rows that satisfy predicate.
+//
+// The first row of selected range is used as header row.
+func (r *Range) Filter(predicate func(rec Record) bool) *View {
+	header := r.header()
+	view := &View{Source: r, Header: header}
+	date1904 := r.File != nil && r.File.Date1904
+	rows := r.Rows(nil)
+	for rows.Next() {
+		if rows.RowNumber() == r.Row {
+			continue
+		}
+		if predicate(newRecord(rows.RowNumber(), header, rows.Row(), date1904)) {
+			view.Rows = append(view.Rows, rows.RowNumber())
+		}
+	}
+	return view
+}
+
+// FilterByCriteria returns rows that satisfy criteria range like Excel AdvancedFilter.
+//
+// The first row of criteria is header names and other rows are conditions.
+// Conditions in the same row are combined with AND, and rows are combined with OR.
+// Conditions are written in Excel style like "Open", "=Open", ">1000", "<>", "A*".
+// Blank condition rows match all rows like Excel. Header names are compared after trimming spaces.
+func (r *Range) FilterByCriteria(criteria *Range) (*View, error) {
+	header := r.header()
+	criteriaHeader := criteria.header()
+	rowCount, _ := criteria.size()
+	if rowCount < 2 {
+		return nil, fmt.Errorf("Criteria range %s should have header row and condition rows", criteria.Format(true))
+	}
+	type condition struct {
+		column int
+		match  criterion
+	}
+	var clauses [][]condition
+	for row := criteria.Row + 1; row < criteria.Row+rowCount; row++ {
+		var clause []condition
+		for i, name := range criteriaHeader {
+			// criteria range may be larger than populated cells
+			cell := criteria.cellAt(row-1, criteria.Column+i-1)
+			if cell == nil || cell.Value == "" {
+				continue
+			}
+			column := indexOf(header, name)
+			if column == -1 {
+				return nil, fmt.Errorf("Criteria column '%s' is not found in header of %s", name, r.Format(true))
+			}
+			clause = append(clause, condition{column: column, match: compileCriterion(cell.Value, true)})
+		}
+		clauses = append(clauses, clause)
+	}
+	return r.Filter(func(rec Record) bool {
+		for _, clause := range clauses {
+			matched := true
+			for _, condition := range clause {
+				if !condition.match(rec.Cells[condition.column]) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// Len returns count of selected rows
+func (v *View) Len() int {
+	return len(v.Rows)
+}
+
+// Records returns selected rows as records
+func (v *View) Records() []Record {
+	_, columnCount := v.Source.size()
+	date1904 := v.Source.File != nil && v.Source.File.Date1904
+	result := make([]Record, len(v.Rows))
+	for i, rowNumber := range v.Rows {
+		cells := make([]*xlsx.Cell, columnCount)
+		for column := range cells {
+			cells[column] = v.Source.cellAt(rowNumber-1, v.Source.Column+column-1)
+		}
+		result[i] = newRecord(rowNumber, v.Header, cells, date1904)
+	}
+	return result
+}
+
+// CopyTo copies header row and selected rows to left top corner of dest.
+// It returns the range that was actually written.
+func (v *View) CopyTo(dest *Range) *Range {
+	_, columnCount := v.Source.size()
+	rowNumbers := append([]int{v.Source.Row}, v.Rows...)
+	for i, rowNumber := range rowNumbers {
+		for column := 0; column < columnCount; column++ {
+			src := v.Source.cellAt(rowNumber-1, v.Source.Column+column-1)
+			copyCell(dest.Sheet.Cell(dest.Row+i-1, dest.Column+column-1), src)
+		}
+	}
+	return New(dest.Sheet, dest.Row, dest.Column, len(rowNumbers), columnCount)
+}
+
+// header returns values of the first row in selected range
+func (r *Range) header() []string {
+	_, columnCount := r.size()
+	header := make([]string, columnCount)
+	for column := range header {
+		if cell := r.cellAt(r.Row-1, r.Column+column-1); cell != nil {
+			header[column] = strings.TrimSpace(cell.Value)
+		}
+	}
+	return header
+}
+
+func newRecord(rowNumber int, header []string, cells []*xlsx.Cell, date1904 bool) Record {
+	record := Record{
+		RowNumber: rowNumber,
+		Header:    header,
+		Values:    make([]interface{}, len(cells)),
+		Cells:     make([]*xlsx.Cell, len(cells)),
+	}
+	copy(record.Cells, cells)
+	for i, cell := range cells {
+		record.Values[i] = typedValue(cell, date1904)
+	}
+	return record
+}
+
+func indexOf(values []string, value string) int {
+	for i, candidate := range values {
+		if candidate == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,105 @@
+package xlsxrange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func createTableFile() *xlsx.File {
+	file := xlsx.NewFile()
+	sheet, _ := file.AddSheet("Data")
+	New(sheet, "A1").ReadCSV(strings.NewReader(strings.Join([]string{
+		"Status,Amount,Owner",
+		"Open,1500,alice",
+		"Closed,3000,bob",
+		"Open,500,carol",
+		"Open,2000,dave",
+		"Pending,1200,erin",
+	}, "\n")), &ImportOptions{InferTypes: true})
+	return file
+}
+
+func TestFilter(t *testing.T) {
+	file := createTableFile()
+	aRange := New(file.Sheet["Data"], "A1:C6")
+
+	view := aRange.Filter(func(rec Record) bool {
+		amount, _ := rec.Float("Amount")
+		return rec.String("Status") == "Open" && amount > 1000
+	})
+	if view.Len() != 2 {
+		t.Errorf("Filter should return 2 rows, but %d", view.Len())
+	}
+	records := view.Records()
+	if records[1].String("Owner") != "dave" {
+		t.Errorf("second owner should be 'dave', but %s", records[1].String("Owner"))
+	}
+	if records[1].RowNumber != 5 {
+		t.Errorf("second row number should be 5, but %d", records[1].RowNumber)
+	}
+}
+
+func TestFilterByCriteria(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	criteria := New(sheet, "E1")
+	criteria.ReadCSV(strings.NewReader("Status,Amount\nOpen,>1000\nP,\n"), nil)
+	criteria.Select("E1:F3")
+
+	view, err := New(sheet, "A1:C6").FilterByCriteria(criteria)
+	if err != nil {
+		t.Errorf("FilterByCriteria should not return error, but %s", err)
+	}
+	if view.Len() != 3 {
+		t.Errorf("FilterByCriteria should return 3 rows, but %d", view.Len())
+	}
+
+	written := view.CopyTo(New(sheet, "H1"))
+	if written.Format(false) != "H1:J4" {
+		t.Errorf("written range should be 'H1:J4', but %s", written.Format(false))
+	}
+	if written.GetCellAt(3, 2).Value != "erin" {
+		t.Errorf("J4 should be 'erin', but %s", written.GetCellAt(3, 2).Value)
+	}
+}
+
+func TestFilterByCriteriaBeyondData(t *testing.T) {
+	file := createTableFile()
+	criteriaSheet, _ := file.AddSheet("Criteria")
+	criteriaSheet.Cell(0, 0).SetString(" Status ")
+	criteriaSheet.Cell(1, 0).SetString("Open")
+
+	view, err := New(file.Sheet["Data"], "A1:C6").FilterByCriteria(New(criteriaSheet, "A1:C2"))
+	if err != nil {
+		t.Fatalf("FilterByCriteria should not return error, but %s", err)
+	}
+	if view.Len() != 3 {
+		t.Errorf("FilterByCriteria should return 3 rows, but %d", view.Len())
+	}
+}
+
+func TestCompileCriterion(t *testing.T) {
+	cell := &xlsx.Cell{}
+	cell.SetString("Apple")
+	if !compileCriterion("a*e", false)(cell) {
+		t.Errorf("'a*e' should match 'Apple'")
+	}
+	if compileCriterion("App", false)(cell) {
+		t.Errorf("'App' should not match 'Apple' without prefix match")
+	}
+	if !compileCriterion("App", true)(cell) {
+		t.Errorf("'App' should match 'Apple' with prefix match")
+	}
+	if !compileCriterion("<>Banana", false)(cell) {
+		t.Errorf("'<>Banana' should match 'Apple'")
+	}
+	cell.SetInt(10)
+	if !compileCriterion(">=10", false)(cell) {
+		t.Errorf("'>=10' should match 10")
+	}
+	if compileCriterion("<10", false)(cell) {
+		t.Errorf("'<10' should not match 10")
+	}
+}
@@ -0,0 +1,142 @@
+package xlsxrange
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/tealeg/xlsx"
+)
+
+// MatchMode specifies how Range.Find compares query and cell values
+type MatchMode int
+
+const (
+	MatchExact     MatchMode = iota // Whole cell value should be equal to query
+	MatchSubstring                  // Cell value should contain query
+	MatchRegexp                     // Query is a regular expression (regexp package syntax)
+)
+
+// FindOptions controls search of Range.Find and Range.Replace.
+// nil is acceptable and it means zero value of this struct.
+type FindOptions struct {
+	Mode       MatchMode // Match mode
+	IgnoreCase bool      // Case insensitive match
+	Formatted  bool      // Matches displayed (formatted) values instead of raw values. Replace ignores this flag.
+}
+
+// Find returns the first matched cell in selected range as single cell range.
+// It returns nil if there is no matched cell. Cells are scanned row by row.
+func (r *Range) Find(query string, opts *FindOptions) (*Range, error) {
+	result, err := r.find(query, opts, 1)
+	if err != nil || len(result) == 0 {
+		return nil, err
+	}
+	return result[0], nil
+}
+
+// FindAll returns all matched cells in selected range as single cell ranges.
+func (r *Range) FindAll(query string, opts *FindOptions) ([]*Range, error) {
+	return r.find(query, opts, -1)
+}
+
+// Replace rewrites the first matched cell whose value is changed by the replacement and returns count of changed cells.
+//
+// In MatchSubstring mode, replacement is inserted literally.
+// In MatchRegexp mode, $1 style references in replacement are expanded.
+// Formula cells are skipped to keep their formulas.
+func (r *Range) Replace(query, replacement string, opts *FindOptions) (int, error) {
+	return r.replace(query, replacement, opts, 1)
+}
+
+// ReplaceAll rewrites all matched cells and returns count of changed cells.
+func (r *Range) ReplaceAll(query, replacement string, opts *FindOptions) (int, error) {
+	return r.replace(query, replacement, opts, -1)
+}
+
+func (r *Range) find(query string, opts *FindOptions, limit int) ([]*Range, error) {
+	if opts == nil {
+		opts = &FindOptions{}
+	}
+	pattern, err := compileQuery(query, opts)
+	if err != nil {
+		return nil, err
+	}
+	exportOpts := &ExportOptions{Formatted: opts.Formatted}
+	var result []*Range
+	rows := r.Rows(nil)
+	for rows.Next() {
+		for column, cell := range rows.Row() {
+			if cell == nil || !pattern.MatchString(r.cellText(cell, exportOpts)) {
+				continue
+			}
+			result = append(result, New(r.Sheet, rows.RowNumber(), r.Column+column))
+			if len(result) == limit {
+				return result, nil
+			}
+		}
+	}
+	return result, rows.Err()
+}
+
+func (r *Range) replace(query, replacement string, opts *FindOptions, limit int) (int, error) {
+	if opts == nil {
+		opts = &FindOptions{}
+	}
+	pattern, err := compileQuery(query, opts)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	rows := r.Rows(nil)
+	for rows.Next() {
+		for _, cell := range rows.Row() {
+			// cached value of formula cell is a result of the formula, so it is not a target
+			if cell == nil || cell.Formula() != "" || !pattern.MatchString(cell.Value) {
+				continue
+			}
+			var newValue string
+			if opts.Mode == MatchRegexp {
+				newValue = pattern.ReplaceAllString(cell.Value, replacement)
+			} else {
+				newValue = pattern.ReplaceAllLiteralString(cell.Value, replacement)
+			}
+			if newValue == cell.Value {
+				continue
+			}
+			setReplacedValue(cell, newValue)
+			count++
+			if count == limit {
+				return count, nil
+			}
+		}
+	}
+	return count, rows.Err()
+}
+
+// compileQuery converts query into regular expression
+func compileQuery(query string, opts *FindOptions) (*regexp.Regexp, error) {
+	var expression string
+	switch opts.Mode {
+	case MatchRegexp:
+		expression = query
+	case MatchSubstring:
+		expression = regexp.QuoteMeta(query)
+	default:
+		expression = "^(?:" + regexp.QuoteMeta(query) + ")$"
+	}
+	if opts.IgnoreCase {
+		expression = "(?i)" + expression
+	}
+	return regexp.Compile(expression)
+}
+
+// setReplacedValue keeps numeric cell numeric if replaced value is still a number
+func setReplacedValue(cell *xlsx.Cell, value string) {
+	if cell.Type() == xlsx.CellTypeNumeric {
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			cell.Value = value
+			return
+		}
+	}
+	cell.SetString(value)
+}
@@ -0,0 +1,101 @@
+package xlsxrange
+
+import "testing"
+
+func TestFind(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "A1:J15")
+
+	found, err := aRange.Find("c12", &FindOptions{IgnoreCase: true})
+	if err != nil {
+		t.Errorf("Find should not return error, but %s", err)
+	}
+	if found == nil || found.Format(false) != "C12" {
+		t.Errorf("Find should return 'C12', but %v", found)
+	}
+
+	found, _ = aRange.Find("c12", nil)
+	if found != nil {
+		t.Errorf("Find should be case sensitive by default, but %s", found.Format(false))
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "A1:J15")
+
+	found, _ := aRange.FindAll("E1", &FindOptions{Mode: MatchSubstring})
+	if len(found) != 7 {
+		t.Errorf("FindAll should return 7 cells (E1, E10-E15), but %d", len(found))
+	}
+
+	found, _ = aRange.FindAll(`^[AB]1[0-9]$`, &FindOptions{Mode: MatchRegexp})
+	if len(found) != 12 {
+		t.Errorf("FindAll should return 12 cells, but %d", len(found))
+	}
+	if found[1].Format(false) != "B10" {
+		t.Errorf("second result should be 'B10', but %s", found[1].Format(false))
+	}
+
+	_, err := aRange.FindAll(`[`, &FindOptions{Mode: MatchRegexp})
+	if err == nil {
+		t.Errorf("FindAll should return error for invalid regexp")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "A1:B2")
+
+	count, _ := aRange.Replace("1", "-one", &FindOptions{Mode: MatchSubstring})
+	if count != 1 {
+		t.Errorf("Replace should change 1 cell, but %d", count)
+	}
+	if aRange.GetCellAt(0, 0).Value != "A-one" {
+		t.Errorf("A1 should be 'A-one', but %s", aRange.GetCellAt(0, 0).Value)
+	}
+
+	count, _ = aRange.ReplaceAll(`([A-Z])(\d)`, "$2$1", &FindOptions{Mode: MatchRegexp})
+	if count != 3 {
+		t.Errorf("ReplaceAll should change 3 cells, but %d", count)
+	}
+	if aRange.GetCellAt(1, 1).Value != "2B" {
+		t.Errorf("B2 should be '2B', but %s", aRange.GetCellAt(1, 1).Value)
+	}
+}
+
+func TestReplaceCountsOnlyChangedCells(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "A1:B2")
+
+	count, _ := aRange.ReplaceAll("x*", "", &FindOptions{Mode: MatchRegexp})
+	if count != 0 {
+		t.Errorf("ReplaceAll should not count unchanged cells, but %d", count)
+	}
+	count, _ = aRange.Replace("[AB]1", "A1", &FindOptions{Mode: MatchRegexp})
+	if count != 1 {
+		t.Errorf("Replace should change 1 cell, but %d", count)
+	}
+	if aRange.GetCellAt(0, 1).Value != "A1" {
+		t.Errorf("Replace should skip unchanged A1 and change B1, but %s", aRange.GetCellAt(0, 1).Value)
+	}
+}
+
+func TestReplaceSkipsFormula(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "A1:B2")
+	formulaCell := aRange.GetCellAt(0, 1)
+	formulaCell.SetFormula(`"B"&1`)
+	formulaCell.Value = "B1"
+
+	count, _ := aRange.ReplaceAll("B", "C", &FindOptions{Mode: MatchSubstring})
+	if count != 1 {
+		t.Errorf("ReplaceAll should change 1 cell, but %d", count)
+	}
+	if formulaCell.Formula() != `"B"&1` {
+		t.Errorf("formula of B1 should be kept, but %s", formulaCell.Formula())
+	}
+	if aRange.GetCellAt(1, 1).Value != "C2" {
+		t.Errorf("B2 should be 'C2', but %s", aRange.GetCellAt(1, 1).Value)
+	}
+}
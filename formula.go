@@ -0,0 +1,308 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var cellReferencePattern *regexp.Regexp = regexp.MustCompile(`(\$?)([A-Za-z]{1,3})(\$?)([1-9][0-9]*)`)
+
+// shiftFormula moves relative cell references (A1, $A1, A$1) in formula by rows and columns.
+// Absolute parts ($A$1) are kept. References moved out of the sheet become #REF!.
+func shiftFormula(formula string, rows, columns int) string {
+	return mapFormulaReferences(formula, func(ref formulaReference, _ *formulaReference) string {
+		if !ref.absCol {
+			ref.column += columns
+		}
+		if !ref.absRow {
+			ref.row += rows
+		}
+		if ref.column < 1 || ref.row < 1 {
+			return "#REF!"
+		}
+		return ref.String()
+	})
+}
+
+// formulaReference is a cell reference in formula
+type formulaReference struct {
+	sheet  string // Sheet name. It is empty if the reference doesn't have sheet name.
+	absCol bool
+	column int
+	absRow bool
+	row    int
+}
+
+// String returns the reference without sheet name
+func (ref formulaReference) String() string {
+	return formatReference(ref.absCol, ref.column, ref.absRow, ref.row)
+}
+
+var quotedSheetNamePattern = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+// mapFormulaReferences replaces cell references in formula outside of string literals.
+// Sheet names (like Sheet1! or 'Sheet 1'!) are kept. For the second reference of an area (like B5 of A1:B5),
+// mapper also receives the first reference as areaStart and the second reference has the same sheet name.
+func mapFormulaReferences(formula string, mapper func(ref formulaReference, areaStart *formulaReference) string) string {
+	return mapFormulaAreas(formula, func(start formulaReference, end *formulaReference) string {
+		if end == nil {
+			return mapper(start, nil)
+		}
+		return mapper(start, nil) + ":" + mapper(*end, &start)
+	})
+}
+
+// mapFormulaAreas replaces cell references and areas (like A1:B5) in formula outside of string literals.
+// end of mapper is nil for a single reference, and the result replaces the whole area.
+// Sheet names are kept and the second reference of an area has the same sheet name as the first one.
+func mapFormulaAreas(formula string, mapper func(start formulaReference, end *formulaReference) string) string {
+	return mapOutsideStrings(formula, func(segment string) string {
+		quoted := quotedSheetNamePattern.FindAllStringIndex(segment, -1)
+		type located struct {
+			ref           formulaReference
+			begin, finish int
+		}
+		var refs []located
+	references:
+		for _, match := range cellReferencePattern.FindAllStringSubmatchIndex(segment, -1) {
+			begin, finish := match[0], match[1]
+			if begin > 0 && isNameChar(segment[begin-1]) {
+				continue
+			}
+			if finish < len(segment) && (isNameChar(segment[finish]) || segment[finish] == '(') {
+				continue
+			}
+			for _, span := range quoted {
+				if span[0] <= begin && begin < span[1] {
+					continue references
+				}
+			}
+			column := ColumnStrToNumber(segment[match[4]:match[5]])
+			if column > 16384 {
+				continue
+			}
+			row, _ := strconv.Atoi(segment[match[8]:match[9]])
+			ref := formulaReference{sheet: sheetNameBefore(segment, begin, quoted), absCol: match[3] > match[2], column: column, absRow: match[7] > match[6], row: row}
+			refs = append(refs, located{ref: ref, begin: begin, finish: finish})
+		}
+		var buffer bytes.Buffer
+		last := 0
+		for i := 0; i < len(refs); i++ {
+			start := refs[i]
+			buffer.WriteString(segment[last:start.begin])
+			if i+1 < len(refs) && refs[i+1].begin == start.finish+1 && segment[start.finish] == ':' {
+				end := refs[i+1].ref
+				end.sheet = start.ref.sheet
+				buffer.WriteString(mapper(start.ref, &end))
+				last = refs[i+1].finish
+				i++
+				continue
+			}
+			buffer.WriteString(mapper(start.ref, nil))
+			last = start.finish
+		}
+		buffer.WriteString(segment[last:])
+		return buffer.String()
+	})
+}
+
+// sheetNameBefore returns sheet name of the reference that starts at begin of segment, or "".
+// quoted is positions of quoted sheet names in segment.
+func sheetNameBefore(segment string, begin int, quoted [][]int) string {
+	if begin == 0 || segment[begin-1] != '!' {
+		return ""
+	}
+	for _, span := range quoted {
+		if span[1] == begin-1 {
+			return strings.Replace(segment[span[0]+1:span[1]-1], "''", "'", -1)
+		}
+	}
+	start := begin - 1
+	for start > 0 && isNameChar(segment[start-1]) {
+		start--
+	}
+	return segment[start : begin-1]
+}
+
+// mapOutsideStrings replaces parts of formula outside of string literals by mapper.
+func mapOutsideStrings(formula string, mapper func(segment string) string) string {
+	var buffer bytes.Buffer
+	inString := false
+	start := 0
+	flush := func(end int) {
+		segment := formula[start:end]
+		if inString {
+			buffer.WriteString(segment)
+		} else {
+			buffer.WriteString(mapper(segment))
+		}
+	}
+	for i := 0; i < len(formula); i++ {
+		if formula[i] == '"' {
+			if inString {
+				flush(i + 1)
+				start = i + 1
+				inString = false
+			} else {
+				flush(i)
+				start = i
+				inString = true
+			}
+		}
+	}
+	flush(len(formula))
+	return buffer.String()
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '.' || ('0' <= c && c <= '9') || ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z')
+}
+
+func formatReference(absCol bool, column int, absRow bool, row int) string {
+	var buffer bytes.Buffer
+	if absCol {
+		buffer.WriteByte('$')
+	}
+	buffer.WriteString(NumberToColumnStr(column))
+	if absRow {
+		buffer.WriteByte('$')
+	}
+	buffer.WriteString(strconv.Itoa(row))
+	return buffer.String()
+}
+
+// referenceFormula returns absolute reference of the range with sheet name for formulas (like 'Sheet 1'!$A$1:$B$3).
+func referenceFormula(r *Range) string {
+	var buffer bytes.Buffer
+	buffer.WriteString(quoteSheetName(r.Sheet.Name))
+	buffer.WriteByte('!')
+	rowCount, columnCount := r.NumRows, r.NumColumns
+	switch {
+	case r.NumRows == AllRows && r.NumColumns == AllColumns:
+		buffer.WriteString("$1:$1048576")
+	case r.NumRows == AllRows:
+		buffer.WriteString("$" + NumberToColumnStr(r.Column) + ":$" + NumberToColumnStr(r.Column+columnCount-1))
+	case r.NumColumns == AllColumns:
+		buffer.WriteString("$" + strconv.Itoa(r.Row) + ":$" + strconv.Itoa(r.Row+rowCount-1))
+	default:
+		buffer.WriteString(formatReference(true, r.Column, true, r.Row))
+		if rowCount != 1 || columnCount != 1 {
+			buffer.WriteByte(':')
+			buffer.WriteString(formatReference(true, r.Column+columnCount-1, true, r.Row+rowCount-1))
+		}
+	}
+	return buffer.String()
+}
+
+var simpleSheetNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+var cellLikeSheetNamePattern = regexp.MustCompile(`^[A-Za-z]{1,3}[0-9]+$`)
+
+// quoteSheetName quotes sheet name for formulas if needed
+func quoteSheetName(name string) string {
+	if simpleSheetNamePattern.MatchString(name) && !cellLikeSheetNamePattern.MatchString(name) {
+		return name
+	}
+	return "'" + strings.Replace(name, "'", "''", -1) + "'"
+}
+
+// refersSheet returns true if the reference refers the sheet. References without sheet name refer
+// the sheet only when the formula is in the sheet (local is true).
+func (ref formulaReference) refersSheet(sheetName string, local bool) bool {
+	if ref.sheet == "" {
+		return local
+	}
+	return strings.EqualFold(ref.sheet, sheetName)
+}
+
+// insertRowsInFormula moves references at or below row at (1 origin) of sheet sheetName by count like
+// inserting rows in Excel. local should be true if the formula is in the sheet.
+//
+// Areas that end at the row just above at and start at or below expandFrom (like SUM(B5:B5) for a
+// repeated row 5) are expanded to include inserted rows. Zero expandFrom disables expansion.
+func insertRowsInFormula(formula, sheetName string, local bool, at, count, expandFrom int) string {
+	return mapFormulaReferences(formula, func(ref formulaReference, areaStart *formulaReference) string {
+		if !ref.refersSheet(sheetName, local) {
+			return ref.String()
+		}
+		if ref.row >= at {
+			ref.row += count
+		} else if expandFrom != 0 && areaStart != nil && ref.row == at-1 && areaStart.row >= expandFrom {
+			ref.row += count
+		}
+		return ref.String()
+	})
+}
+
+// deleteRowsInFormula moves references below deleted rows of sheet sheetName up like deleting rows in Excel.
+// local should be true if the formula is in the sheet. Areas that contain deleted rows shrink, and references
+// and areas whose rows are all deleted become #REF!.
+func deleteRowsInFormula(formula, sheetName string, local bool, at, count int) string {
+	return mapFormulaAreas(formula, func(start formulaReference, end *formulaReference) string {
+		if !start.refersSheet(sheetName, local) {
+			if end == nil {
+				return start.String()
+			}
+			return start.String() + ":" + end.String()
+		}
+		if end == nil {
+			if start.row >= at+count {
+				start.row -= count
+			} else if start.row >= at {
+				return "#REF!"
+			}
+			return start.String()
+		}
+		top, bottom := &start, end
+		if bottom.row < top.row {
+			top, bottom = bottom, top
+		}
+		if top.row >= at && bottom.row < at+count {
+			return "#REF!"
+		}
+		if top.row >= at+count {
+			top.row -= count
+		} else if top.row >= at {
+			// the first row below deleted rows moves to at
+			top.row = at
+		}
+		if bottom.row >= at+count {
+			bottom.row -= count
+		} else if bottom.row >= at {
+			bottom.row = at - 1
+		}
+		return start.String() + ":" + end.String()
+	})
+}
+
+// moveCellsInFormula moves references to cells in source of sheet sheetName to the cells returned by position
+// like moving cells in Excel. local should be true if the formula is in the sheet. Areas are moved only if they
+// are inside source. References to cells in overwritten that are not moved become #REF!.
+func moveCellsInFormula(formula, sheetName string, local bool, source, overwritten *Range, position func(row, column int) (int, int)) string {
+	return mapFormulaAreas(formula, func(start formulaReference, end *formulaReference) string {
+		if !start.refersSheet(sheetName, local) {
+			if end == nil {
+				return start.String()
+			}
+			return start.String() + ":" + end.String()
+		}
+		last := start
+		if end != nil {
+			last = *end
+		}
+		inside := func(area *Range) bool {
+			return area.contains(start.row, start.column) && area.contains(last.row, last.column)
+		}
+		if inside(source) {
+			start.row, start.column = position(start.row, start.column)
+			last.row, last.column = position(last.row, last.column)
+		} else if inside(overwritten) {
+			return "#REF!"
+		}
+		if end == nil {
+			return start.String()
+		}
+		return start.String() + ":" + last.String()
+	})
+}
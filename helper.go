@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -54,3 +55,8 @@ func ColumnStrToNumber(s string) int {
 	}
 	return result
 }
+
+// cellName returns A1 notation of a cell (row and column are 1 origin)
+func cellName(row, column int) string {
+	return NumberToColumnStr(column) + strconv.Itoa(row)
+}
@@ -0,0 +1,242 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tealeg/xlsx"
+)
+
+// DefaultDateLayouts are used to infer date values when ImportOptions.DateLayouts is empty
+var DefaultDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// ImportOptions controls how external data is written into cells.
+// nil is acceptable and it means zero value of this struct.
+type ImportOptions struct {
+	InferTypes  bool     // Converts numeric, boolean and date like strings into typed cells
+	DateLayouts []string // Go time layouts used for date inference. Default is DefaultDateLayouts.
+	Comma       rune     // Field delimiter of CSV. Default is ','
+}
+
+// ReadCSV writes CSV data into cells from left top corner of selected range.
+//
+// The sheet is expanded if it doesn't have enough rows or columns.
+// It returns the range that was actually written.
+func (r *Range) ReadCSV(reader io.Reader, opts *ImportOptions) (*Range, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	if opts.Comma != 0 {
+		csvReader.Comma = opts.Comma
+	}
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]interface{}, len(records))
+	for i, record := range records {
+		row := make([]interface{}, len(record))
+		for j, field := range record {
+			row[j] = field
+		}
+		values[i] = row
+	}
+	return r.writeValues(values, opts), nil
+}
+
+// ReadJSON writes JSON data into cells from left top corner of selected range.
+//
+// It accepts array of arrays or array of objects. In object style, keys are written as a header row
+// in the order of their first appearance.
+// The sheet is expanded if it doesn't have enough rows or columns.
+// It returns the range that was actually written.
+func (r *Range) ReadJSON(reader io.Reader, opts *ImportOptions) (*Range, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	var items []json.RawMessage
+	if err := json.NewDecoder(reader).Decode(&items); err != nil {
+		return nil, err
+	}
+	var values [][]interface{}
+	var keys []string
+	keyIndex := make(map[string]int)
+	objectStyle := false
+	for i, item := range items {
+		trimmed := bytes.TrimSpace(item)
+		if len(trimmed) == 0 {
+			continue
+		}
+		switch trimmed[0] {
+		case '[':
+			var row []interface{}
+			if err := decodeJSON(trimmed, &row); err != nil {
+				return nil, err
+			}
+			values = append(values, row)
+		case '{':
+			objectStyle = true
+			object, err := decodeOrderedObject(trimmed)
+			if err != nil {
+				return nil, err
+			}
+			row := make([]interface{}, len(keys))
+			for _, pair := range object {
+				index, ok := keyIndex[pair.key]
+				if !ok {
+					index = len(keys)
+					keyIndex[pair.key] = index
+					keys = append(keys, pair.key)
+					row = append(row, nil)
+				}
+				row[index] = pair.value
+			}
+			values = append(values, row)
+		default:
+			return nil, fmt.Errorf("Element %d should be array or object, but %s", i, string(trimmed))
+		}
+	}
+	if objectStyle {
+		header := make([]interface{}, len(keys))
+		for i, key := range keys {
+			header[i] = key
+		}
+		for i, row := range values {
+			for len(row) < len(keys) {
+				row = append(row, nil)
+			}
+			values[i] = row
+		}
+		values = append([][]interface{}{header}, values...)
+	}
+	return r.writeValues(values, opts), nil
+}
+
+type keyValue struct {
+	key   string
+	value interface{}
+}
+
+// decodeOrderedObject decodes JSON object with keeping key order
+func decodeOrderedObject(data []byte) ([]keyValue, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	var result []keyValue
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		result = append(result, keyValue{key: token.(string), value: value})
+	}
+	return result, nil
+}
+
+func decodeJSON(data []byte, value interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(value)
+}
+
+// writeValues writes values from left top corner of selected range and returns written range
+func (r *Range) writeValues(values [][]interface{}, opts *ImportOptions) *Range {
+	columnCount := 0
+	for rowIndex, row := range values {
+		if len(row) > columnCount {
+			columnCount = len(row)
+		}
+		for columnIndex, value := range row {
+			cell := r.Sheet.Cell(r.Row+rowIndex-1, r.Column+columnIndex-1)
+			setCellValue(cell, value, opts)
+		}
+	}
+	return New(r.Sheet, r.Row, r.Column, len(values), columnCount)
+}
+
+// setCellValue sets value to cell. String values are converted when opts.InferTypes is true.
+func setCellValue(cell *xlsx.Cell, value interface{}, opts *ImportOptions) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			cell.SetInt64(i)
+		} else if f, err := v.Float64(); err == nil {
+			cell.SetFloat(f)
+		} else {
+			cell.SetString(v.String())
+		}
+	case bool:
+		cell.SetBool(v)
+	case string:
+		if opts.InferTypes {
+			inferCellValue(cell, v, opts)
+		} else {
+			cell.SetString(v)
+		}
+	case nil:
+		cell.SetString("")
+	default:
+		data, _ := json.Marshal(v)
+		cell.SetString(string(data))
+	}
+}
+
+func inferCellValue(cell *xlsx.Cell, text string, opts *ImportOptions) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		cell.SetString(text)
+		return
+	}
+	if i, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+		cell.SetInt64(i)
+		return
+	}
+	if f, err := strconv.ParseFloat(trimmed, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		cell.SetFloat(f)
+		return
+	}
+	switch strings.ToLower(trimmed) {
+	case "true":
+		cell.SetBool(true)
+		return
+	case "false":
+		cell.SetBool(false)
+		return
+	}
+	layouts := opts.DateLayouts
+	if len(layouts) == 0 {
+		layouts = DefaultDateLayouts
+	}
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, trimmed)
+		if err != nil {
+			continue
+		}
+		if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+			cell.SetDate(t)
+		} else {
+			cell.SetDateTime(t)
+		}
+		return
+	}
+	cell.SetString(text)
+}
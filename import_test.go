@@ -0,0 +1,84 @@
+package xlsxrange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestReadCSV(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "I14")
+
+	written, err := aRange.ReadCSV(strings.NewReader("a,b,c\n1,2,3\n"), nil)
+	if err != nil {
+		t.Errorf("ReadCSV should not return error, but %s", err)
+	}
+	if written.Format(false) != "I14:K15" {
+		t.Errorf("written range should be 'I14:K15', but %s", written.Format(false))
+	}
+	if aRange.GetCellAt(1, 2).Value != "3" {
+		t.Errorf("K15 should be '3', but %s", aRange.GetCellAt(1, 2).Value)
+	}
+	if aRange.GetCellAt(1, 2).Type() != xlsx.CellTypeString {
+		t.Errorf("K15 should be string without type inference")
+	}
+}
+
+func TestReadCSVWithInferTypes(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "A1")
+
+	aRange.ReadCSV(strings.NewReader("12,1.5,true,2016-04-01,text\n"), &ImportOptions{InferTypes: true})
+	if aRange.GetCellAt(0, 0).Type() != xlsx.CellTypeNumeric {
+		t.Errorf("A1 should be numeric")
+	}
+	if aRange.GetCellAt(0, 1).Value != "1.5" {
+		t.Errorf("B1 should be '1.5', but %s", aRange.GetCellAt(0, 1).Value)
+	}
+	if aRange.GetCellAt(0, 2).Type() != xlsx.CellTypeBool {
+		t.Errorf("C1 should be boolean")
+	}
+	if !aRange.GetCellAt(0, 3).IsTime() {
+		t.Errorf("D1 should be date")
+	}
+	if aRange.GetCellAt(0, 4).Type() != xlsx.CellTypeString {
+		t.Errorf("E1 should be string")
+	}
+}
+
+func TestReadJSONObjects(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "B2")
+
+	written, err := aRange.ReadJSON(strings.NewReader(`[{"name": "apple", "price": 100}, {"price": 80, "name": "orange", "stock": true}]`), nil)
+	if err != nil {
+		t.Errorf("ReadJSON should not return error, but %s", err)
+	}
+	if written.Format(false) != "B2:D4" {
+		t.Errorf("written range should be 'B2:D4', but %s", written.Format(false))
+	}
+	if aRange.GetCellAt(0, 1).Value != "price" {
+		t.Errorf("C2 should be 'price', but %s", aRange.GetCellAt(0, 1).Value)
+	}
+	if aRange.GetCellAt(2, 0).Value != "orange" {
+		t.Errorf("B4 should be 'orange', but %s", aRange.GetCellAt(2, 0).Value)
+	}
+	if aRange.GetCellAt(2, 1).Type() != xlsx.CellTypeNumeric {
+		t.Errorf("C4 should be numeric")
+	}
+	if aRange.GetCellAt(1, 2).Value != "" {
+		t.Errorf("D3 should be empty, but %s", aRange.GetCellAt(1, 2).Value)
+	}
+}
+
+func TestReadJSONArrays(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "A1")
+
+	written, _ := aRange.ReadJSON(strings.NewReader(`[["a", 1], ["b", 2, 3]]`), nil)
+	if written.Format(false) != "A1:C2" {
+		t.Errorf("written range should be 'A1:C2', but %s", written.Format(false))
+	}
+}
@@ -0,0 +1,91 @@
+package xlsxrange
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/tealeg/xlsx"
+)
+
+const (
+	maxColumnWidth   = 255 // Maximum column width of Excel in characters
+	maxRowHeight     = 409 // Maximum row height of Excel in points
+	defaultRowHeight = 15  // Row height of Excel for default font in points
+)
+
+// SetColumnWidth sets width (in characters of default font) of columns of selected range.
+// Whole-column selections like "B:D" are supported.
+func (r *Range) SetColumnWidth(width float64) error {
+	if width < 0 || width > maxColumnWidth {
+		return fmt.Errorf("Column width should be between 0 and %d, but %g", maxColumnWidth, width)
+	}
+	area := r.clamp()
+	_, columnCount := area.size()
+	for column := area.Column; column < area.Column+columnCount; column++ {
+		sheetColumn(r.Sheet, column).Width = width
+	}
+	return nil
+}
+
+// SetRowHeight sets height (in points) of rows of selected range
+func (r *Range) SetRowHeight(height float64) error {
+	if height < 0 || height > maxRowHeight {
+		return fmt.Errorf("Row height should be between 0 and %d, but %g", maxRowHeight, height)
+	}
+	area := r.clamp()
+	rowCount, _ := area.size()
+	for row := area.Row; row < area.Row+rowCount; row++ {
+		r.Sheet.Row(row - 1).SetHeight(height)
+	}
+	return nil
+}
+
+// AutoFit estimates widths of columns and heights of rows of selected range from formatted text
+// and font size of cells, because the actual size depends on fonts of the viewer.
+//
+// Columns without text keep their widths. Rows are made taller only if they have multi-line text
+// or large fonts. Merged cells that span multiple columns are not used for column widths.
+func (r *Range) AutoFit() {
+	area := r.clamp()
+	rowCount, columnCount := area.size()
+	widths := make([]float64, columnCount)
+	// column width unit and default row height are based on default font
+	baseSize := float64(xlsx.DefaultFont().Size)
+	for row := area.Row; row < area.Row+rowCount; row++ {
+		height := 0.0
+		for column := 0; column < columnCount; column++ {
+			cell := r.cellAt(row-1, area.Column+column-1)
+			if cell == nil || cell.Value == "" {
+				continue
+			}
+			text, err := cell.FormattedValue()
+			if err != nil {
+				text = cell.Value
+			}
+			scale, bold := 1.0, false
+			if style := cellStyle(cell); style != nil && style.Font.Size > 0 {
+				scale, bold = float64(style.Font.Size)/baseSize, style.Font.Bold
+			}
+			lines := strings.Split(text, "\n")
+			if cell.HMerge == 0 {
+				for _, line := range lines {
+					width := float64(displayWidth(line)) * scale
+					if bold {
+						width *= 1.1
+					}
+					widths[column] = math.Max(widths[column], width+2) // padding of both sides
+				}
+			}
+			height = math.Max(height, math.Ceil(float64(len(lines))*defaultRowHeight*scale))
+		}
+		if height > defaultRowHeight {
+			r.Sheet.Row(row - 1).SetHeight(math.Min(height, maxRowHeight))
+		}
+	}
+	for column, width := range widths {
+		if width > 0 {
+			sheetColumn(r.Sheet, area.Column+column).Width = math.Min(math.Ceil(width), maxColumnWidth)
+		}
+	}
+}
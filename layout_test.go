@@ -0,0 +1,81 @@
+package xlsxrange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestSetColumnWidth(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 1"]
+	if err := New(sheet, "B:D").SetColumnWidth(20); err != nil {
+		t.Fatalf("SetColumnWidth should succeed, but %s", err.Error())
+	}
+	for column := 2; column <= 4; column++ {
+		if width := columnSetting(sheet, column).Width; width != 20 {
+			t.Errorf("width of column %d should be 20, but %g", column, width)
+		}
+	}
+	if width := columnSetting(sheet, 5).Width; width == 20 {
+		t.Errorf("width of column E should not be changed")
+	}
+	if err := New(sheet, "B:D").SetColumnWidth(300); err == nil {
+		t.Errorf("too wide column should be error")
+	}
+	parts, _ := MarshallParts(file)
+	if xml := parts["xl/worksheets/sheet1.xml"]; !strings.Contains(xml, `max="2" min="2" style="1" width="20" customWidth="true"`) {
+		t.Errorf("column width should be written, but %s", xml)
+	}
+}
+
+func TestSetRowHeight(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 1"]
+	if err := New(sheet, "A2:C3").SetRowHeight(30); err != nil {
+		t.Fatalf("SetRowHeight should succeed, but %s", err.Error())
+	}
+	if sheet.Rows[1].Height != 30 || sheet.Rows[2].Height != 30 || sheet.Rows[3].Height == 30 {
+		t.Errorf("rows 2 and 3 should be 30pt, but %g", sheet.Rows[1].Height)
+	}
+	if err := New(sheet, "A2").SetRowHeight(-1); err == nil {
+		t.Errorf("negative height should be error")
+	}
+}
+
+func TestAutoFit(t *testing.T) {
+	file := xlsx.NewFile()
+	sheet, _ := file.AddSheet("Data")
+	sheet.Cell(0, 0).SetString("ID")
+	sheet.Cell(0, 1).SetString("Description of the item")
+	sheet.Cell(1, 1).SetString("line1\nline2")
+	sheet.Cell(0, 2).SetString("日本語")
+	style := xlsx.NewStyle()
+	style.Font.Size = 24
+	sheet.Cell(2, 0).SetString("Big")
+	sheet.Cell(2, 0).SetStyle(style)
+	New(sheet, "A:C").AutoFit()
+
+	if width := columnSetting(sheet, 2).Width; width != 25 {
+		t.Errorf("column B should fit the longest text, but %g", width)
+	}
+	if width := columnSetting(sheet, 3).Width; width != 8 {
+		t.Errorf("wide characters should count as two, but %g", width)
+	}
+	if width := columnSetting(sheet, 1).Width; width != 8 {
+		t.Errorf("column A should fit large font, but %g", width)
+	}
+	if height := sheet.Rows[1].Height; height != 30 {
+		t.Errorf("row with two lines should be 30pt, but %g", height)
+	}
+	if height := sheet.Rows[2].Height; height != 30 {
+		t.Errorf("row with large font should be 30pt, but %g", height)
+	}
+	if height := sheet.Rows[0].Height; height != 0 {
+		t.Errorf("row with single line should keep default height, but %g", height)
+	}
+	if cellStyle(sheet.Cell(0, 0)) != nil {
+		t.Errorf("AutoFit should not add style to cells")
+	}
+}
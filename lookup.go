@@ -0,0 +1,247 @@
+package xlsxrange
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tealeg/xlsx"
+)
+
+// ErrNotFound is returned by lookup functions when there is no matched value
+var ErrNotFound = errors.New("Lookup value is not found")
+
+// LookupMatchMode is match mode of Range.XLookup
+type LookupMatchMode int
+
+const (
+	LookupExact              LookupMatchMode = iota // Exact match
+	LookupExactOrNextSmaller                        // Exact match or next smaller item
+	LookupExactOrNextLarger                         // Exact match or next larger item
+	LookupWildcard                                  // Wildcard match (*, ? and ~)
+)
+
+// LookupSearchMode is search mode of Range.XLookup
+type LookupSearchMode int
+
+const (
+	SearchFirstToLast      LookupSearchMode = iota // Searches from the first item
+	SearchLastToFirst                              // Searches from the last item
+	SearchBinaryAscending                          // Binary search on data sorted in ascending order
+	SearchBinaryDescending                         // Binary search on data sorted in descending order
+)
+
+// XLookupOptions controls Range.XLookup.
+// nil is acceptable and it means zero value of this struct.
+type XLookupOptions struct {
+	MatchMode  LookupMatchMode
+	SearchMode LookupSearchMode
+	IfNotFound *Range // Returned instead of ErrNotFound if it is not nil
+}
+
+// VLookup searches key in the first column of selected range and returns the cell in colIndex column of the row.
+//
+// colIndex is 1 origin like Excel. If exact is false, the first column should be sorted in ascending order
+// and the row of the largest value that is less than or equal to key is used.
+func (r *Range) VLookup(key interface{}, colIndex int, exact bool) (*Range, error) {
+	rowCount, columnCount := r.size()
+	if colIndex < 1 || colIndex > columnCount {
+		return nil, fmt.Errorf("Column index %d is out of range (1 - %d)", colIndex, columnCount)
+	}
+	index := matchIndex(r.vector(rowCount, func(i int) (int, int) { return i, 0 }), key, exact)
+	if index == -1 {
+		return nil, ErrNotFound
+	}
+	return New(r.Sheet, r.Row+index, r.Column+colIndex-1), nil
+}
+
+// HLookup searches key in the first row of selected range and returns the cell in rowIndex row of the column.
+//
+// rowIndex is 1 origin like Excel. See VLookup for exact flag.
+func (r *Range) HLookup(key interface{}, rowIndex int, exact bool) (*Range, error) {
+	rowCount, columnCount := r.size()
+	if rowIndex < 1 || rowIndex > rowCount {
+		return nil, fmt.Errorf("Row index %d is out of range (1 - %d)", rowIndex, rowCount)
+	}
+	index := matchIndex(r.vector(columnCount, func(i int) (int, int) { return 0, i }), key, exact)
+	if index == -1 {
+		return nil, ErrNotFound
+	}
+	return New(r.Sheet, r.Row+rowIndex-1, r.Column+index), nil
+}
+
+// XLookup searches key in selected range (single row or single column) and returns the cell
+// at the same position in returnRange.
+//
+// It returns error like Excel #VALUE! if returnRange doesn't have the same number of rows
+// (or columns for a single row) as selected range.
+func (r *Range) XLookup(key interface{}, returnRange *Range, opts *XLookupOptions) (*Range, error) {
+	if opts == nil {
+		opts = &XLookupOptions{}
+	}
+	cells, vertical, err := r.lookupVector()
+	if err != nil {
+		return nil, err
+	}
+	returnRows, returnColumns := returnRange.size()
+	if (vertical && returnRows != len(cells)) || (!vertical && returnColumns != len(cells)) {
+		return nil, fmt.Errorf("Return range %s should have the same size as lookup range %s", returnRange.Format(false), r.Format(false))
+	}
+	index := xlookupIndex(cells, key, opts)
+	if index == -1 {
+		if opts.IfNotFound != nil {
+			return opts.IfNotFound, nil
+		}
+		return nil, ErrNotFound
+	}
+	if vertical {
+		return New(returnRange.Sheet, returnRange.Row+index, returnRange.Column), nil
+	}
+	return New(returnRange.Sheet, returnRange.Row, returnRange.Column+index), nil
+}
+
+// Match returns relative position (1 origin) of key in selected range (single row or single column).
+//
+// matchType is same as Excel MATCH: 1 finds the largest value that is less than or equal to key
+// (sorted in ascending order), 0 finds exactly equal value and -1 finds the smallest value that is
+// greater than or equal to key (sorted in descending order).
+func (r *Range) Match(key interface{}, matchType int) (int, error) {
+	cells, _, err := r.lookupVector()
+	if err != nil {
+		return 0, err
+	}
+	var index int
+	switch {
+	case matchType == 0:
+		index = matchIndex(cells, key, true)
+	case matchType > 0:
+		index = matchIndex(cells, key, false)
+	default:
+		index = xlookupIndex(cells, key, &XLookupOptions{
+			MatchMode:  LookupExactOrNextLarger,
+			SearchMode: SearchBinaryDescending,
+		})
+	}
+	if index == -1 {
+		return 0, ErrNotFound
+	}
+	return index + 1, nil
+}
+
+// Index returns the cell at relative position (1 origin) in selected range like Excel INDEX.
+func (r *Range) Index(row, col int) (*Range, error) {
+	rowCount, columnCount := r.size()
+	if row < 1 || row > rowCount || col < 1 || col > columnCount {
+		return nil, fmt.Errorf("Index (%d, %d) is out of range %s", row, col, r.Format(false))
+	}
+	return New(r.Sheet, r.Row+row-1, r.Column+col-1), nil
+}
+
+// vector returns cells in selected range at relative positions returned by position
+func (r *Range) vector(count int, position func(i int) (int, int)) []*xlsx.Cell {
+	cells := make([]*xlsx.Cell, count)
+	for i := range cells {
+		row, column := position(i)
+		cells[i] = r.cellAt(r.Row+row-1, r.Column+column-1)
+	}
+	return cells
+}
+
+// lookupVector returns cells of single row or single column range
+func (r *Range) lookupVector() ([]*xlsx.Cell, bool, error) {
+	rowCount, columnCount := r.size()
+	if columnCount == 1 {
+		return r.vector(rowCount, func(i int) (int, int) { return i, 0 }), true, nil
+	} else if rowCount == 1 {
+		return r.vector(columnCount, func(i int) (int, int) { return 0, i }), false, nil
+	}
+	return nil, false, fmt.Errorf("Lookup range %s should be single row or single column", r.Format(false))
+}
+
+// keyCell converts lookup key into a cell to compare with compareCells
+func keyCell(key interface{}) *xlsx.Cell {
+	if cell, ok := key.(*xlsx.Cell); ok {
+		return cell
+	}
+	cell := &xlsx.Cell{}
+	switch value := key.(type) {
+	case bool:
+		cell.SetBool(value)
+	default:
+		cell.SetValue(value)
+	}
+	return cell
+}
+
+// matchIndex searches key like VLOOKUP and MATCH. It returns -1 if it is not found.
+func matchIndex(cells []*xlsx.Cell, key interface{}, exact bool) int {
+	if exact {
+		return xlookupIndex(cells, key, &XLookupOptions{MatchMode: LookupWildcard})
+	}
+	return xlookupIndex(cells, key, &XLookupOptions{
+		MatchMode:  LookupExactOrNextSmaller,
+		SearchMode: SearchBinaryAscending,
+	})
+}
+
+func xlookupIndex(cells []*xlsx.Cell, key interface{}, opts *XLookupOptions) int {
+	target := keyCell(key)
+	equal := func(cell *xlsx.Cell) bool {
+		return compareCells(cell, target, false) == 0
+	}
+	if text, ok := key.(string); ok && opts.MatchMode == LookupWildcard && strings.ContainsAny(text, "*?~") {
+		pattern := wildcardToRegexp(text, false)
+		equal = func(cell *xlsx.Cell) bool {
+			return cellKind(cell) == kindText && pattern.MatchString(cell.Value)
+		}
+	}
+
+	switch opts.SearchMode {
+	case SearchBinaryAscending, SearchBinaryDescending:
+		descending := opts.SearchMode == SearchBinaryDescending
+		// the first position whose value is greater than key in sort order
+		upper := sort.Search(len(cells), func(i int) bool {
+			return compareCells(cells[i], target, descending) > 0
+		})
+		if upper > 0 && equal(cells[upper-1]) {
+			return upper - 1
+		}
+		nextSmaller := opts.MatchMode == LookupExactOrNextSmaller
+		nextLarger := opts.MatchMode == LookupExactOrNextLarger
+		if (nextSmaller && !descending) || (nextLarger && descending) {
+			return upper - 1
+		} else if (nextLarger && !descending) || (nextSmaller && descending) {
+			if upper < len(cells) && cellKind(cells[upper]) != kindBlank {
+				return upper
+			}
+		}
+		return -1
+	}
+
+	best := -1
+	for i := range cells {
+		index := i
+		if opts.SearchMode == SearchLastToFirst {
+			index = len(cells) - 1 - i
+		}
+		cell := cells[index]
+		if equal(cell) {
+			return index
+		}
+		if cellKind(cell) == kindBlank || cellKind(cell) != cellKind(target) {
+			continue
+		}
+		switch opts.MatchMode {
+		case LookupExactOrNextSmaller:
+			if compareCells(cell, target, false) < 0 && (best == -1 || compareCells(cell, cells[best], false) > 0) {
+				best = index
+			}
+		case LookupExactOrNextLarger:
+			if compareCells(cell, target, false) > 0 && (best == -1 || compareCells(cell, cells[best], false) < 0) {
+				best = index
+			}
+		}
+	}
+	return best
+}
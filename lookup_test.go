@@ -0,0 +1,108 @@
+package xlsxrange
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVLookup(t *testing.T) {
+	file := createTableFile()
+	lookup := New(file.Sheet["Data"], "B2:C6")
+	lookup.Sort(SortKey{Column: 0})
+	result, err := lookup.VLookup(2000, 2, true)
+	if err != nil || result.GetCell().Value != "dave" {
+		t.Errorf("VLookup exact should return 'dave', but %v %v", result, err)
+	}
+	result, err = lookup.VLookup(1400, 2, false)
+	if err != nil || result.Format(false) != "C3" {
+		t.Errorf("VLookup approximate should return C3, but %v %v", result, err)
+	}
+	_, err = lookup.VLookup(100, 2, false)
+	if err != ErrNotFound {
+		t.Errorf("VLookup approximate should return ErrNotFound, but %v", err)
+	}
+	_, err = lookup.VLookup(1400, 2, true)
+	if err != ErrNotFound {
+		t.Errorf("VLookup exact should return ErrNotFound, but %v", err)
+	}
+}
+
+func TestHLookup(t *testing.T) {
+	file := createTableFile()
+	aRange := New(file.Sheet["Data"], "A1:C6")
+
+	result, err := aRange.HLookup("own*", 3, true)
+	if err != nil || result.GetCell().Value != "bob" {
+		t.Errorf("HLookup should return 'bob', but %v %v", result, err)
+	}
+}
+
+func TestXLookup(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	status := New(sheet, "A2:A6")
+	owners := New(sheet, "C2:C6")
+
+	result, err := owners.XLookup("carol", New(sheet, "B2:B6"), nil)
+	if err != nil || result.GetCell().Value != "500" {
+		t.Errorf("XLookup should return 500, but %v %v", result, err)
+	}
+
+	result, _ = status.XLookup("Open", owners, &XLookupOptions{SearchMode: SearchLastToFirst})
+	if result.GetCell().Value != "dave" {
+		t.Errorf("XLookup from last should return 'dave', but %s", result.GetCell().Value)
+	}
+	result, _ = New(sheet, "B2:B6").XLookup(1300, owners, &XLookupOptions{MatchMode: LookupExactOrNextLarger})
+	if result.GetCell().Value != "alice" {
+		t.Errorf("XLookup next larger should return 'alice', but %s", result.GetCell().Value)
+	}
+	fallback := New(sheet, "A1")
+	result, _ = status.XLookup("Unknown", owners, &XLookupOptions{IfNotFound: fallback})
+	if result != fallback {
+		t.Errorf("XLookup should return IfNotFound")
+	}
+}
+
+func TestXLookupReturnRangeSizeMismatch(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+
+	if _, err := New(sheet, "C2:C6").XLookup("carol", New(sheet, "B2:B4"), nil); err == nil {
+		t.Errorf("XLookup should return error for shorter return range")
+	}
+	if _, err := New(sheet, "A1:C1").XLookup("amount", New(sheet, "A2:B2"), nil); err == nil {
+		t.Errorf("XLookup should return error for narrower return range")
+	}
+	result, err := New(sheet, "A1:C1").XLookup("amount", New(sheet, "A2:C2"), nil)
+	if err != nil || result.Format(false) != "B2" {
+		t.Errorf("XLookup should return B2, but %v %v", result, err)
+	}
+}
+
+func TestMatchAndIndex(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+
+	position, err := New(sheet, "A1:C1").Match("amount", 0)
+	if err != nil || position != 2 {
+		t.Errorf("Match should return 2, but %d %v", position, err)
+	}
+	New(sheet, "E1").ReadCSV(strings.NewReader("50\n40\n30\n20\n"), &ImportOptions{InferTypes: true})
+	position, _ = New(sheet, "E1:E4").Match(35, -1)
+	if position != 2 {
+		t.Errorf("Match(-1) should return 2, but %d", position)
+	}
+	position, _ = New(sheet, "E1:E4").Match(35, 0)
+	if position != 0 {
+		t.Errorf("Match(0) should not find 35, but %d", position)
+	}
+
+	cell, err := New(sheet, "A1:C6").Index(3, 3)
+	if err != nil || cell.GetCell().Value != "bob" {
+		t.Errorf("Index should return 'bob', but %v %v", cell, err)
+	}
+	_, err = New(sheet, "A1:C6").Index(7, 1)
+	if err == nil {
+		t.Errorf("Index should return error for out of range")
+	}
+}
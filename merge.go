@@ -0,0 +1,141 @@
+package xlsxrange
+
+import (
+	"fmt"
+
+	"github.com/tealeg/xlsx"
+)
+
+// GetMergedCell returns left top corner cell from selected range considering merged cells.
+// See GetMergedCellAt.
+func (r *Range) GetMergedCell() *xlsx.Cell {
+	return r.GetMergedCellAt(0, 0)
+}
+
+// GetMergedCellAt returns cell at relative location (0 origin) from selected range.
+//
+// If the cell is covered by merged cells, it returns the anchor (left top) cell of the merge
+// that holds the value. Only cells to the up-left of the reference are searched for the anchor.
+func (r *Range) GetMergedCellAt(refRow, refCol int) *xlsx.Cell {
+	if anchor := r.mergeAnchor(r.Row+refRow-1, r.Column+refCol-1); anchor != nil {
+		return anchor
+	}
+	return r.GetCellAt(refRow, refCol)
+}
+
+// mergeAnchor returns the anchor cell of the merged area that covers the cell (0 origin)
+func (r *Range) mergeAnchor(row, column int) *xlsx.Cell {
+	for rowIndex := min(row, len(r.Sheet.Rows)-1); rowIndex >= 0; rowIndex-- {
+		sheetRow := r.Sheet.Rows[rowIndex]
+		if sheetRow == nil {
+			continue
+		}
+		for columnIndex := min(column, len(sheetRow.Cells)-1); columnIndex >= 0; columnIndex-- {
+			cell := sheetRow.Cells[columnIndex]
+			if cell != nil && (cell.HMerge > 0 || cell.VMerge > 0) &&
+				row <= rowIndex+cell.VMerge && column <= columnIndex+cell.HMerge {
+				return cell
+			}
+		}
+	}
+	return nil
+}
+
+// GetMergedCells returns cells in selected range considering merged cells.
+// Cells covered by merged cells are replaced with their anchor cells.
+func (r *Range) GetMergedCells() [][]*xlsx.Cell {
+	cells := r.GetCells()
+	for _, merge := range r.Merges() {
+		mergeRows, mergeColumns := merge.size()
+		anchor := merge.GetCell()
+		for row := merge.Row; row < merge.Row+mergeRows; row++ {
+			for column := merge.Column; column < merge.Column+mergeColumns; column++ {
+				if r.contains(row, column) {
+					cells[row-r.Row][column-r.Column] = anchor
+				}
+			}
+		}
+	}
+	return cells
+}
+
+// Merges returns merged areas that intersect with selected range.
+func (r *Range) Merges() []*Range {
+	var result []*Range
+	for _, merge := range r.sheetMerges() {
+		if r.intersects(merge) {
+			result = append(result, merge)
+		}
+	}
+	return result
+}
+
+// Merge merges selected cells. The left top cell becomes the anchor.
+//
+// Existing merged areas inside selected range are absorbed. It returns error if an existing
+// merged area lies across the border of selected range.
+func (r *Range) Merge() error {
+	rowCount, columnCount := r.size()
+	if rowCount == 0 || columnCount == 0 {
+		return fmt.Errorf("Range %s is empty", r.Format(false))
+	}
+	merges := r.Merges()
+	for _, merge := range merges {
+		if !r.includes(merge) {
+			return fmt.Errorf("Merged cells %s overlap the border of %s", merge.Format(false), r.Format(false))
+		}
+	}
+	for _, merge := range merges {
+		merge.unmergeAnchor()
+	}
+	anchor := r.Sheet.Cell(r.Row-1, r.Column-1)
+	anchor.HMerge = columnCount - 1
+	anchor.VMerge = rowCount - 1
+	return nil
+}
+
+// Unmerge splits all merged areas that intersect with selected range.
+func (r *Range) Unmerge() {
+	for _, merge := range r.Merges() {
+		merge.unmergeAnchor()
+	}
+}
+
+func (r *Range) unmergeAnchor() {
+	if anchor := r.cellAt(r.Row-1, r.Column-1); anchor != nil {
+		anchor.HMerge = 0
+		anchor.VMerge = 0
+	}
+}
+
+// sheetMerges returns all merged areas in the sheet
+func (r *Range) sheetMerges() []*Range {
+	var result []*Range
+	for rowIndex, row := range r.Sheet.Rows {
+		if row == nil {
+			continue
+		}
+		for columnIndex, cell := range row.Cells {
+			if cell != nil && (cell.HMerge > 0 || cell.VMerge > 0) {
+				result = append(result, New(r.Sheet, rowIndex+1, columnIndex+1, cell.VMerge+1, cell.HMerge+1))
+			}
+		}
+	}
+	return result
+}
+
+// intersects returns true if selected range and other range share any cells
+func (r *Range) intersects(other *Range) bool {
+	rowCount, columnCount := r.size()
+	otherRows, otherColumns := other.size()
+	return r.Row < other.Row+otherRows && other.Row < r.Row+rowCount &&
+		r.Column < other.Column+otherColumns && other.Column < r.Column+columnCount
+}
+
+// includes returns true if other range is completely inside of selected range
+func (r *Range) includes(other *Range) bool {
+	rowCount, columnCount := r.size()
+	otherRows, otherColumns := other.size()
+	return r.Row <= other.Row && other.Row+otherRows <= r.Row+rowCount &&
+		r.Column <= other.Column && other.Column+otherColumns <= r.Column+columnCount
+}
@@ -0,0 +1,71 @@
+package xlsxrange
+
+import "testing"
+
+func TestGetMergedCellAt(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	New(sheet, "B2:C3").Merge()
+
+	aRange := New(sheet, "C3")
+	if aRange.GetCell().Value != "C3" {
+		t.Errorf("GetCell should return covered cell itself, but %s", aRange.GetCell().Value)
+	}
+	if aRange.GetMergedCell().Value != "B2" {
+		t.Errorf("GetMergedCell should return anchor cell 'B2', but %s", aRange.GetMergedCell().Value)
+	}
+
+	cells := New(sheet, "C2:D3").GetMergedCells()
+	if cells[1][0].Value != "B2" || cells[1][1].Value != "D3" {
+		t.Errorf("GetMergedCells should return anchor for merged cells, but %s %s", cells[1][0].Value, cells[1][1].Value)
+	}
+}
+
+func TestGetMergedCellAtOutsideMerges(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	New(sheet, "B2:C3").Merge()
+	New(sheet, "A5:B5").Merge()
+
+	for _, ref := range []string{"D3", "C4", "A1", "C5"} {
+		if cell := New(sheet, ref).GetMergedCell(); cell.Value != ref {
+			t.Errorf("GetMergedCell should return %s itself, but %s", ref, cell.Value)
+		}
+	}
+	if cell := New(sheet, "B5").GetMergedCell(); cell.Value != "A5" {
+		t.Errorf("GetMergedCell should return anchor cell 'A5', but %s", cell.Value)
+	}
+	if cell := New(sheet, "B2").GetMergedCell(); cell.Value != "B2" {
+		t.Errorf("GetMergedCell should return anchor cell itself, but %s", cell.Value)
+	}
+}
+
+func TestMergesAndUnmerge(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	New(sheet, "B2:C3").Merge()
+	New(sheet, "E5:E8").Merge()
+
+	merges := New(sheet, "C3:E5").Merges()
+	if len(merges) != 2 {
+		t.Errorf("Merges should return 2 areas, but %d", len(merges))
+	}
+	if merges[1].Format(false) != "E5:E8" {
+		t.Errorf("second merge should be 'E5:E8', but %s", merges[1].Format(false))
+	}
+
+	if err := New(sheet, "A1:C2").Merge(); err == nil {
+		t.Errorf("Merge should return error when it overlaps existing merge partially")
+	}
+	if err := New(sheet, "A1:C3").Merge(); err != nil {
+		t.Errorf("Merge should absorb inner merge, but %s", err)
+	}
+	if sheet.Rows[1].Cells[1].HMerge != 0 {
+		t.Errorf("inner merge should be removed")
+	}
+
+	New(sheet, "E6").Unmerge()
+	if len(New(sheet, "A1:J15").Merges()) != 1 {
+		t.Errorf("Unmerge should remove merge of E5:E8")
+	}
+}
@@ -0,0 +1,224 @@
+package xlsxrange
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/tealeg/xlsx"
+)
+
+// maxOutlineLevel is the deepest outline level that Excel supports
+const maxOutlineLevel = 7
+
+// HideRows hides rows of selected range.
+// tealeg/xlsx doesn't write hidden rows, so use xlsxrange.Save or xlsxrange.Write to keep them hidden.
+func (r *Range) HideRows() {
+	r.setRowsHidden(true)
+}
+
+// UnhideRows shows rows of selected range
+func (r *Range) UnhideRows() {
+	r.setRowsHidden(false)
+}
+
+// HideColumns hides columns of selected range
+func (r *Range) HideColumns() {
+	r.setColumnsHidden(true)
+}
+
+// UnhideColumns shows columns of selected range
+func (r *Range) UnhideColumns() {
+	r.setColumnsHidden(false)
+}
+
+func (r *Range) setRowsHidden(hidden bool) {
+	area := r.clamp()
+	rowCount, _ := area.size()
+	for row := area.Row; row < area.Row+rowCount; row++ {
+		r.Sheet.Row(row - 1).Hidden = hidden
+	}
+}
+
+func (r *Range) setColumnsHidden(hidden bool) {
+	area := r.clamp()
+	_, columnCount := area.size()
+	for column := area.Column; column < area.Column+columnCount; column++ {
+		sheetColumn(r.Sheet, column).Hidden = hidden
+	}
+}
+
+// GroupRows adds rows of selected range to an outline group. Nested groups are made by calling it
+// for inner rows again. If collapsed is true, rows are hidden and the group is shown as collapsed.
+//
+// Rows outside of the range keep their outline levels, so the row below the range becomes
+// summary row of the group like Excel. Collapsed state is written only by xlsxrange.Save or xlsxrange.Write.
+func (r *Range) GroupRows(collapsed bool) error {
+	area := r.clamp()
+	rowCount, _ := area.size()
+	for row := area.Row; row < area.Row+rowCount; row++ {
+		if r.Sheet.Row(row-1).OutlineLevel >= maxOutlineLevel {
+			return fmt.Errorf("Row %d already has the deepest outline level", row)
+		}
+	}
+	for row := area.Row; row < area.Row+rowCount; row++ {
+		sheetRow := r.Sheet.Row(row - 1)
+		sheetRow.OutlineLevel++
+		if collapsed {
+			sheetRow.Hidden = true
+		}
+	}
+	if collapsed {
+		extensions.Lock()
+		defer extensions.Unlock()
+		ext := sheetExtensionOf(r.Sheet)
+		if ext.collapsedRows == nil {
+			ext.collapsedRows = make(map[int]bool)
+		}
+		// summary row should exist to have collapsed attribute
+		r.Sheet.Row(area.Row + rowCount - 1)
+		ext.collapsedRows[area.Row+rowCount] = true
+	}
+	return nil
+}
+
+// UngroupRows removes rows of selected range from the innermost outline group.
+// Rows hidden by collapsed group are shown.
+func (r *Range) UngroupRows() {
+	area := r.clamp()
+	rowCount, _ := area.size()
+	for row := area.Row; row < area.Row+rowCount && row-1 < len(r.Sheet.Rows); row++ {
+		sheetRow := r.Sheet.Rows[row-1]
+		if sheetRow == nil || sheetRow.OutlineLevel == 0 {
+			continue
+		}
+		sheetRow.OutlineLevel--
+		sheetRow.Hidden = false
+	}
+	extensions.Lock()
+	defer extensions.Unlock()
+	if ext := findSheetExtension(r.Sheet); ext != nil {
+		delete(ext.collapsedRows, area.Row+rowCount)
+	}
+}
+
+// GroupColumns adds columns of selected range to an outline group. Nested groups are made by calling it
+// for inner columns again. If collapsed is true, columns are hidden and the group is shown as collapsed.
+func (r *Range) GroupColumns(collapsed bool) error {
+	area := r.clamp()
+	_, columnCount := area.size()
+	for column := area.Column; column < area.Column+columnCount; column++ {
+		if col := columnSetting(r.Sheet, column); col != nil && col.OutlineLevel >= maxOutlineLevel {
+			return fmt.Errorf("Column %s already has the deepest outline level", xlsx.ColIndexToLetters(column-1))
+		}
+	}
+	for column := area.Column; column < area.Column+columnCount; column++ {
+		col := sheetColumn(r.Sheet, column)
+		col.OutlineLevel++
+		if collapsed {
+			col.Hidden = true
+		}
+	}
+	if collapsed {
+		sheetColumn(r.Sheet, area.Column+columnCount).Collapsed = true
+	}
+	return nil
+}
+
+// UngroupColumns removes columns of selected range from the innermost outline group.
+// Columns hidden by collapsed group are shown.
+func (r *Range) UngroupColumns() {
+	area := r.clamp()
+	_, columnCount := area.size()
+	for column := area.Column; column < area.Column+columnCount; column++ {
+		col := columnSetting(r.Sheet, column)
+		if col == nil || col.OutlineLevel == 0 {
+			continue
+		}
+		col = sheetColumn(r.Sheet, column)
+		col.OutlineLevel--
+		col.Hidden = false
+	}
+	if col := columnSetting(r.Sheet, area.Column+columnCount); col != nil && col.Collapsed {
+		sheetColumn(r.Sheet, area.Column+columnCount).Collapsed = false
+	}
+}
+
+// columnSetting returns column setting that covers the column (1 origin) or nil. It doesn't modify settings.
+func columnSetting(sheet *xlsx.Sheet, column int) *xlsx.Col {
+	for _, col := range sheet.Cols {
+		if col != nil && col.Min <= column && column <= col.Max {
+			return col
+		}
+	}
+	return nil
+}
+
+// rowCollapsed returns true if the row (1 origin) is hidden in an outline group
+func (r *Range) rowCollapsed(row int) bool {
+	if row-1 >= len(r.Sheet.Rows) || r.Sheet.Rows[row-1] == nil {
+		return false
+	}
+	sheetRow := r.Sheet.Rows[row-1]
+	return sheetRow.Hidden && sheetRow.OutlineLevel > 0
+}
+
+// skipRow returns true if the row (1 origin) should be skipped with opts
+func (r *Range) skipRow(row int, opts *IterateOptions) bool {
+	if row-1 >= len(r.Sheet.Rows) || r.Sheet.Rows[row-1] == nil || !r.Sheet.Rows[row-1].Hidden {
+		return false
+	}
+	return opts.SkipHidden || opts.SkipCollapsed && r.rowCollapsed(row)
+}
+
+// skipColumn returns true if the column (1 origin) should be skipped with opts
+func (r *Range) skipColumn(column int, opts *IterateOptions) bool {
+	col := columnSetting(r.Sheet, column)
+	if col == nil || !col.Hidden {
+		return false
+	}
+	return opts.SkipHiddenColumns || opts.SkipCollapsed && col.OutlineLevel > 0
+}
+
+// visibleColumns returns column numbers (1 origin) of selected range that are not skipped with opts
+func (r *Range) visibleColumns(opts *IterateOptions) []int {
+	_, columnCount := r.size()
+	columns := make([]int, 0, columnCount)
+	for column := r.Column; column < r.Column+columnCount; column++ {
+		if !r.skipColumn(column, opts) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// GetVisibleCells returns cells like GetCells, but rows and columns skipped by opts are excluded.
+// SkipEmpty of opts is also applied.
+func (r *Range) GetVisibleCells(opts *IterateOptions) [][]*xlsx.Cell {
+	var result [][]*xlsx.Cell
+	rows := r.Rows(opts)
+	for rows.Next() {
+		result = append(result, append([]*xlsx.Cell{}, rows.Row()...))
+	}
+	return result
+}
+
+var rowElementPattern = regexp.MustCompile(`(<row r="(\d+)")([^>]*>)`)
+var rowStateAttributePattern = regexp.MustCompile(` (?:hidden|collapsed)="[^"]*"`)
+
+// writeRowAttributes sets hidden and collapsed attributes of row elements of the sheet part,
+// because tealeg/xlsx doesn't write them. Existing attributes are replaced.
+func writeRowAttributes(worksheet string, sheet *xlsx.Sheet, collapsedRows map[int]bool) string {
+	return rowElementPattern.ReplaceAllStringFunc(worksheet, func(element string) string {
+		match := rowElementPattern.FindStringSubmatch(element)
+		row, _ := strconv.Atoi(match[2])
+		element = match[1]
+		if row-1 < len(sheet.Rows) && sheet.Rows[row-1] != nil && sheet.Rows[row-1].Hidden {
+			element += ` hidden="1"`
+		}
+		if collapsedRows[row] {
+			element += ` collapsed="1"`
+		}
+		return element + rowStateAttributePattern.ReplaceAllString(match[3], "")
+	})
+}
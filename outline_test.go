@@ -0,0 +1,101 @@
+package xlsxrange
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHideRowsAndColumns(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 1"]
+	New(sheet, "2:3").HideRows()
+	New(sheet, "B:C").HideColumns()
+	if !sheet.Rows[1].Hidden || !sheet.Rows[2].Hidden || sheet.Rows[3].Hidden {
+		t.Errorf("rows 2 and 3 should be hidden")
+	}
+	if !columnSetting(sheet, 2).Hidden || !columnSetting(sheet, 3).Hidden || columnSetting(sheet, 4).Hidden {
+		t.Errorf("columns B and C should be hidden")
+	}
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	if xml := parts["xl/worksheets/sheet1.xml"]; !strings.Contains(xml, `<row r="2" hidden="1"`) || strings.Contains(xml, `<row r="4" hidden="1"`) {
+		t.Errorf("hidden rows should be written, but %s", xml)
+	}
+	New(sheet, "2:3").UnhideRows()
+	New(sheet, "B:C").UnhideColumns()
+	if sheet.Rows[1].Hidden || columnSetting(sheet, 2).Hidden {
+		t.Errorf("rows and columns should be shown")
+	}
+}
+
+func TestGroupRows(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 1"]
+	if err := New(sheet, "2:5").GroupRows(false); err != nil {
+		t.Fatalf("GroupRows should succeed, but %s", err.Error())
+	}
+	New(sheet, "3:4").GroupRows(true)
+	if sheet.Rows[1].OutlineLevel != 1 || sheet.Rows[2].OutlineLevel != 2 || !sheet.Rows[2].Hidden || sheet.Rows[1].Hidden {
+		t.Errorf("rows 3-4 should be nested and collapsed, but level %d", sheet.Rows[2].OutlineLevel)
+	}
+	parts, _ := MarshallParts(file)
+	if xml := parts["xl/worksheets/sheet1.xml"]; !strings.Contains(xml, `<row r="5" collapsed="1"`) || !strings.Contains(xml, `<row r="3" hidden="1"`) {
+		t.Errorf("collapsed group should be written, but %s", xml)
+	}
+	New(sheet, "3:4").UngroupRows()
+	if sheet.Rows[2].OutlineLevel != 1 || sheet.Rows[2].Hidden {
+		t.Errorf("rows 3-4 should be ungrouped and shown, but level %d", sheet.Rows[2].OutlineLevel)
+	}
+	parts, _ = MarshallParts(file)
+	if xml := parts["xl/worksheets/sheet1.xml"]; strings.Contains(xml, `collapsed="1"`) {
+		t.Errorf("collapsed mark should be removed, but %s", xml)
+	}
+	for i := 0; i < 6; i++ {
+		New(sheet, "2:5").GroupRows(false)
+	}
+	if err := New(sheet, "2:5").GroupRows(false); err == nil {
+		t.Errorf("GroupRows deeper than 7 levels should be error")
+	}
+}
+
+func TestGroupColumns(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 1"]
+	if err := New(sheet, "B:D").GroupColumns(true); err != nil {
+		t.Fatalf("GroupColumns should succeed, but %s", err.Error())
+	}
+	if col := columnSetting(sheet, 3); col.OutlineLevel != 1 || !col.Hidden {
+		t.Errorf("column C should be grouped and hidden")
+	}
+	if !columnSetting(sheet, 5).Collapsed {
+		t.Errorf("column E should be marked as collapsed")
+	}
+	if _, err := MarshallParts(file); err != nil {
+		t.Errorf("MarshallParts should succeed, but %s", err.Error())
+	}
+	New(sheet, "B:D").UngroupColumns()
+	if col := columnSetting(sheet, 3); col.OutlineLevel != 0 || col.Hidden || columnSetting(sheet, 5).Collapsed {
+		t.Errorf("columns B-D should be ungrouped and shown")
+	}
+}
+
+func TestWriteRowAttributesReplacesExisting(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[0].Hidden = true
+	worksheet := `<row r="1" hidden="0" ht="20"><c r="A1"/></row><row r="2" hidden="1" collapsed="1"/>`
+	expected := `<row r="1" hidden="1" ht="20"><c r="A1"/></row><row r="2"/>`
+	if result := writeRowAttributes(worksheet, sheet, nil); result != expected {
+		t.Errorf("row attributes should be replaced, but %s", result)
+	}
+}
+
+func TestUngroupRowsDoesntRegisterFile(t *testing.T) {
+	file := createWritableFile()
+	New(file.Sheet["Sheet 1"], "A2:B3").UngroupRows()
+	if registered(file) {
+		t.Errorf("UngroupRows should not register the file")
+	}
+}
@@ -0,0 +1,381 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tealeg/xlsx"
+)
+
+// GrandTotalLabel is used as a label of grand total row and columns of pivot tables
+var GrandTotalLabel = "Grand Total"
+
+// PivotBuilder builds crosstab from a header-row range.
+//
+//	summary, err := aRange.Pivot().Rows("Region").Columns("Quarter").
+//		Values(xlsxrange.AggregateSpec{Column: "Amount", Func: xlsxrange.AggregateSum}).
+//		Compute()
+//
+// Compute and WriteTo make static values. PlaceAt places pivot table of Excel instead.
+type PivotBuilder struct {
+	source       *Range
+	rowFields    []string
+	columnFields []string
+	valueFields  []AggregateSpec
+	grandTotals  bool
+	name         string
+	dest         *Range
+}
+
+// Pivot returns pivot builder. The first row of selected range is used as header row.
+func (r *Range) Pivot() *PivotBuilder {
+	return &PivotBuilder{
+		source:      r,
+		grandTotals: true,
+	}
+}
+
+// Rows adds row fields
+func (b *PivotBuilder) Rows(fields ...string) *PivotBuilder {
+	b.rowFields = append(b.rowFields, fields...)
+	return b
+}
+
+// Columns adds column fields
+func (b *PivotBuilder) Columns(fields ...string) *PivotBuilder {
+	b.columnFields = append(b.columnFields, fields...)
+	return b
+}
+
+// Values adds value fields
+func (b *PivotBuilder) Values(specs ...AggregateSpec) *PivotBuilder {
+	b.valueFields = append(b.valueFields, specs...)
+	return b
+}
+
+// GrandTotals enables or disables grand total row and columns. Default is enabled.
+func (b *PivotBuilder) GrandTotals(enabled bool) *PivotBuilder {
+	b.grandTotals = enabled
+	return b
+}
+
+// Name sets name of pivot table that is placed by PlaceAt. Default is "PivotTable1" and so on.
+func (b *PivotBuilder) Name(name string) *PivotBuilder {
+	b.name = name
+	return b
+}
+
+// Compute computes crosstab.
+//
+// The first columns are row field values and others are aggregated values for each column field
+// values combination (joined by " / ") and value field. Keys are sorted in ascending order like Excel.
+func (b *PivotBuilder) Compute() (*Summary, error) {
+	if len(b.valueFields) == 0 {
+		return nil, fmt.Errorf("Pivot of %s needs at least one value field", b.source.Format(true))
+	}
+	header := b.source.header()
+	resolve := func(names []string) ([]int, error) {
+		indexes := make([]int, len(names))
+		for i, name := range names {
+			indexes[i] = indexOf(header, name)
+			if indexes[i] == -1 {
+				return nil, fmt.Errorf("Pivot field '%s' is not found in header of %s", name, b.source.Format(true))
+			}
+		}
+		return indexes, nil
+	}
+	rowIndexes, err := resolve(b.rowFields)
+	if err != nil {
+		return nil, err
+	}
+	columnIndexes, err := resolve(b.columnFields)
+	if err != nil {
+		return nil, err
+	}
+	valueNames := make([]string, len(b.valueFields))
+	for i, spec := range b.valueFields {
+		valueNames[i] = spec.Column
+	}
+	valueIndexes, err := resolve(valueNames)
+	if err != nil {
+		return nil, err
+	}
+
+	date1904 := b.source.File != nil && b.source.File.Date1904
+	var rowKeys, columnKeys [][]interface{}
+	rowGroups := make(map[string][]int)
+	columnGroups := make(map[string][]int)
+	cellGroups := make(map[[2]string][]int)
+	var allRows []int
+	keyOf := func(cells []*xlsx.Cell, indexes []int) []interface{} {
+		key := make([]interface{}, len(indexes))
+		for i, index := range indexes {
+			key[i] = typedValue(cells[index], date1904)
+		}
+		return key
+	}
+	rows := b.source.Rows(nil)
+	for rows.Next() {
+		if rows.RowNumber() == b.source.Row {
+			continue
+		}
+		rowKey := keyOf(rows.Row(), rowIndexes)
+		columnKey := keyOf(rows.Row(), columnIndexes)
+		rowID := groupID(rowKey)
+		columnID := groupID(columnKey)
+		if _, ok := rowGroups[rowID]; !ok {
+			rowKeys = append(rowKeys, rowKey)
+		}
+		if _, ok := columnGroups[columnID]; !ok {
+			columnKeys = append(columnKeys, columnKey)
+		}
+		rowNumber := rows.RowNumber()
+		rowGroups[rowID] = append(rowGroups[rowID], rowNumber)
+		columnGroups[columnID] = append(columnGroups[columnID], rowNumber)
+		cellGroups[[2]string{rowID, columnID}] = append(cellGroups[[2]string{rowID, columnID}], rowNumber)
+		allRows = append(allRows, rowNumber)
+	}
+	sortKeys(rowKeys)
+	sortKeys(columnKeys)
+
+	aggregate := func(rowNumbers []int, valueIndex int) interface{} {
+		if len(rowNumbers) == 0 {
+			return nil
+		}
+		cells := make([]*xlsx.Cell, len(rowNumbers))
+		for i, rowNumber := range rowNumbers {
+			cells[i] = b.source.cellAt(rowNumber-1, b.source.Column+valueIndexes[valueIndex]-1)
+		}
+		return aggregateCells(cells, b.valueFields[valueIndex].Func)
+	}
+	valueLabel := func(columnKey []interface{}, spec AggregateSpec) string {
+		if len(columnKey) == 0 {
+			return spec.name()
+		}
+		parts := make([]string, len(columnKey))
+		for i, value := range columnKey {
+			parts[i] = fmt.Sprintf("%v", value)
+		}
+		label := strings.Join(parts, " / ")
+		if len(b.valueFields) > 1 {
+			label += " " + spec.name()
+		}
+		return label
+	}
+	showColumnTotals := b.grandTotals && len(b.columnFields) > 0
+
+	summary := &Summary{Header: append([]string{}, b.rowFields...)}
+	for _, columnKey := range columnKeys {
+		for _, spec := range b.valueFields {
+			summary.Header = append(summary.Header, valueLabel(columnKey, spec))
+		}
+	}
+	if showColumnTotals {
+		for _, spec := range b.valueFields {
+			summary.Header = append(summary.Header, valueLabel([]interface{}{GrandTotalLabel}, spec))
+		}
+	}
+	for _, rowKey := range rowKeys {
+		rowID := groupID(rowKey)
+		row := append([]interface{}{}, rowKey...)
+		for _, columnKey := range columnKeys {
+			for i := range b.valueFields {
+				row = append(row, aggregate(cellGroups[[2]string{rowID, groupID(columnKey)}], i))
+			}
+		}
+		if showColumnTotals {
+			for i := range b.valueFields {
+				row = append(row, aggregate(rowGroups[rowID], i))
+			}
+		}
+		summary.Rows = append(summary.Rows, row)
+	}
+	if b.grandTotals && len(b.rowFields) > 0 {
+		row := make([]interface{}, len(b.rowFields))
+		row[0] = GrandTotalLabel
+		for _, columnKey := range columnKeys {
+			for i := range b.valueFields {
+				row = append(row, aggregate(columnGroups[groupID(columnKey)], i))
+			}
+		}
+		if showColumnTotals {
+			for i := range b.valueFields {
+				row = append(row, aggregate(allRows, i))
+			}
+		}
+		summary.Rows = append(summary.Rows, row)
+	}
+	return summary, nil
+}
+
+// WriteTo computes crosstab and writes it to left top corner of dest.
+// It returns the range that was actually written.
+func (b *PivotBuilder) WriteTo(dest *Range) (*Range, error) {
+	summary, err := b.Compute()
+	if err != nil {
+		return nil, err
+	}
+	return summary.WriteTo(dest), nil
+}
+
+// PlaceAt places pivot table of Excel at left top corner of dest instead of writing static values.
+// Pivot cache doesn't have records, and Excel computes the pivot table from the source range when the file
+// is opened, so cells of dest are not changed. All header cells of the source range should be unique names.
+//
+// Pivot tables are kept by this package because tealeg/xlsx doesn't support them.
+// Use xlsxrange.Save or xlsxrange.Write to write them into the file.
+func (b *PivotBuilder) PlaceAt(dest *Range) error {
+	if _, err := b.Compute(); err != nil {
+		return err
+	}
+	header := b.source.clamp().header()
+	for i, name := range header {
+		if name == "" || indexOf(header[:i], name) != -1 {
+			return fmt.Errorf("Header of pivot source %s should have unique names, but column %d is '%s'",
+				b.source.Format(true), i+1, name)
+		}
+	}
+	for _, spec := range b.valueFields {
+		if indexOf(header, spec.name()) != -1 {
+			return fmt.Errorf("Value field name '%s' of pivot table should be different from header of %s",
+				spec.name(), b.source.Format(true))
+		}
+	}
+	pivot := *b
+	pivot.rowFields = append([]string{}, b.rowFields...)
+	pivot.columnFields = append([]string{}, b.columnFields...)
+	pivot.valueFields = append([]AggregateSpec{}, b.valueFields...)
+	pivot.dest = New(dest.Sheet, dest.Row, dest.Column, 1, 1)
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext := sheetExtensionOf(dest.Sheet)
+	if pivot.name == "" {
+		pivot.name = fmt.Sprintf("PivotTable%d", len(ext.pivotTables)+1)
+	}
+	for _, other := range ext.pivotTables {
+		if other.name == pivot.name {
+			return fmt.Errorf("Pivot table '%s' already exists in sheet '%s'", pivot.name, dest.Sheet.Name)
+		}
+	}
+	ext.pivotTables = append(ext.pivotTables, &pivot)
+	return nil
+}
+
+// pivotTable writes pivot table part and pivot cache definition part of the pivot table
+func (b *packageBuilder) pivotTable(sheetPart string, pivot *PivotBuilder) error {
+	summary, err := pivot.Compute()
+	if err != nil {
+		return err
+	}
+	source := pivot.source.clamp()
+	header := source.header()
+	cacheID := len(b.pivotCaches) + 1
+
+	var buffer bytes.Buffer
+	buffer.WriteString(`<pivotCacheDefinition xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"` +
+		` saveData="0" refreshOnLoad="1" createdVersion="3" refreshedVersion="3" minRefreshableVersion="3">`)
+	buffer.WriteString(`<cacheSource type="worksheet"><worksheetSource ref="`)
+	buffer.WriteString(source.Format(false))
+	buffer.WriteString(`" sheet="`)
+	xml.EscapeText(&buffer, []byte(source.Sheet.Name))
+	fmt.Fprintf(&buffer, `"/></cacheSource><cacheFields count="%d">`, len(header))
+	for _, name := range header {
+		// items are filled by Excel when the cache is refreshed
+		buffer.WriteString(`<cacheField name="`)
+		xml.EscapeText(&buffer, []byte(name))
+		buffer.WriteString(`" numFmtId="0"><sharedItems containsBlank="1"><m/></sharedItems></cacheField>`)
+	}
+	buffer.WriteString(`</cacheFields></pivotCacheDefinition>`)
+	cachePart := b.addPart("xl/pivotCache/pivotCacheDefinition%d.xml",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheDefinition+xml", buffer.String())
+
+	buffer.Reset()
+	buffer.WriteString(`<pivotTableDefinition xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" name="`)
+	xml.EscapeText(&buffer, []byte(pivot.name))
+	fmt.Fprintf(&buffer, `" cacheId="%d" dataCaption="Values" updatedVersion="3" minRefreshableVersion="3" createdVersion="3"`+
+		` useAutoFormatting="1" itemPrintTitles="1" indent="0" outline="1" outlineData="1"`, cacheID)
+	if !pivot.grandTotals {
+		buffer.WriteString(` rowGrandTotals="0" colGrandTotals="0"`)
+	}
+	location := New(pivot.dest.Sheet, pivot.dest.Row, pivot.dest.Column, len(summary.Rows)+1, len(summary.Header))
+	fmt.Fprintf(&buffer, `><location ref="%s" firstHeaderRow="1" firstDataRow="1" firstDataCol="1"/>`, location.Format(false))
+	fmt.Fprintf(&buffer, `<pivotFields count="%d">`, len(header))
+	for _, name := range header {
+		buffer.WriteString(`<pivotField`)
+		axis := ""
+		if indexOf(pivot.rowFields, name) != -1 {
+			axis = "axisRow"
+		} else if indexOf(pivot.columnFields, name) != -1 {
+			axis = "axisCol"
+		}
+		if axis != "" {
+			fmt.Fprintf(&buffer, ` axis="%s"`, axis)
+		}
+		for _, spec := range pivot.valueFields {
+			if spec.Column == name {
+				buffer.WriteString(` dataField="1"`)
+				break
+			}
+		}
+		buffer.WriteString(` showAll="0"`)
+		if axis != "" {
+			buffer.WriteString(`><items count="1"><item t="default"/></items></pivotField>`)
+		} else {
+			buffer.WriteString(`/>`)
+		}
+	}
+	buffer.WriteString(`</pivotFields>`)
+	writeFields := func(element string, names []string, values bool) {
+		count := len(names)
+		if values {
+			count++
+		}
+		if count == 0 {
+			return
+		}
+		fmt.Fprintf(&buffer, `<%s count="%d">`, element, count)
+		for _, name := range names {
+			fmt.Fprintf(&buffer, `<field x="%d"/>`, indexOf(header, name))
+		}
+		if values {
+			// -2 is the position of values in column fields
+			buffer.WriteString(`<field x="-2"/>`)
+		}
+		fmt.Fprintf(&buffer, `</%s>`, element)
+	}
+	writeFields("rowFields", pivot.rowFields, false)
+	writeFields("colFields", pivot.columnFields, len(pivot.valueFields) > 1)
+	fmt.Fprintf(&buffer, `<dataFields count="%d">`, len(pivot.valueFields))
+	for _, spec := range pivot.valueFields {
+		buffer.WriteString(`<dataField name="`)
+		xml.EscapeText(&buffer, []byte(spec.name()))
+		fmt.Fprintf(&buffer, `" fld="%d" subtotal="%s" baseField="0" baseItem="0"/>`,
+			indexOf(header, spec.Column), totalsFunctions[spec.Func].name)
+	}
+	buffer.WriteString(`</dataFields><pivotTableStyleInfo name="PivotStyleLight16" showRowHeaders="1" showColHeaders="1"` +
+		` showRowStripes="0" showColStripes="0" showLastColumn="1"/></pivotTableDefinition>`)
+	tablePart := b.addPart("xl/pivotTables/pivotTable%d.xml",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.pivotTable+xml", buffer.String())
+
+	b.addRelationship(tablePart, "pivotCacheDefinition", cachePart)
+	b.addRelationship(sheetPart, "pivotTable", tablePart)
+	id := b.addRelationship("xl/workbook.xml", "pivotCacheDefinition", cachePart)
+	b.pivotCaches = append(b.pivotCaches, fmt.Sprintf(`<pivotCache cacheId="%d" r:id="%s"/>`, cacheID, id))
+	return nil
+}
+
+// sortKeys sorts group keys in ascending order like Excel
+func sortKeys(keys [][]interface{}) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		for k := range keys[i] {
+			result := compareCells(keyCell(keys[i][k]), keyCell(keys[j][k]), false)
+			if result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	})
+}
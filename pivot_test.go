@@ -0,0 +1,163 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestPivot(t *testing.T) {
+	file := createTableFile()
+	aRange := New(file.Sheet["Data"], "A1:C6")
+
+	summary, err := aRange.Pivot().Rows("Status").Values(AggregateSpec{Column: "Amount", Func: AggregateSum}).Compute()
+	if err != nil {
+		t.Errorf("Compute should not return error, but %s", err)
+	}
+	expected := [][]interface{}{
+		{"Closed", 3000.0},
+		{"Open", 4000.0},
+		{"Pending", 1200.0},
+		{"Grand Total", 8200.0},
+	}
+	for i, row := range expected {
+		if summary.Rows[i][0] != row[0] || summary.Rows[i][1] != row[1] {
+			t.Errorf("row %d should be %v, but %v", i, row, summary.Rows[i])
+		}
+	}
+}
+
+func TestPivotWithColumns(t *testing.T) {
+	file := xlsx.NewFile()
+	sheet, _ := file.AddSheet("Sales")
+	New(sheet, "A1").ReadCSV(strings.NewReader(strings.Join([]string{
+		"Region,Quarter,Amount",
+		"East,Q2,10",
+		"West,Q1,20",
+		"East,Q1,30",
+		"East,Q2,40",
+	}, "\n")), &ImportOptions{InferTypes: true})
+
+	written, err := New(sheet, "A1:C5").Pivot().Rows("Region").Columns("Quarter").
+		Values(AggregateSpec{Column: "Amount", Func: AggregateSum}).
+		WriteTo(New(sheet, "E1"))
+	if err != nil {
+		t.Errorf("WriteTo should not return error, but %s", err)
+	}
+	if written.Format(false) != "E1:H4" {
+		t.Errorf("written range should be 'E1:H4', but %s", written.Format(false))
+	}
+	expected := [][]string{
+		{"Region", "Q1", "Q2", "Grand Total"},
+		{"East", "30", "50", "80"},
+		{"West", "20", "", "20"},
+		{"Grand Total", "50", "50", "100"},
+	}
+	for i, row := range expected {
+		for j, value := range row {
+			if written.GetCellAt(i, j).Value != value {
+				t.Errorf("cell (%d, %d) should be '%s', but '%s'", i, j, value, written.GetCellAt(i, j).Value)
+			}
+		}
+	}
+}
+
+func TestPivotWithoutValues(t *testing.T) {
+	file := createTableFile()
+	if _, err := New(file.Sheet["Data"], "A1:C6").Pivot().Rows("Status").Compute(); err == nil {
+		t.Errorf("Compute should return error without value fields")
+	}
+}
+
+func TestPivotPlaceAt(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	report, _ := file.AddSheet("Report")
+	err := New(sheet, "A1:C6").Pivot().Rows("Status").Columns("Owner").Name("Sales & Status").
+		Values(AggregateSpec{Column: "Amount", Func: AggregateSum}, AggregateSpec{Column: "Amount", Func: AggregateCountA}).
+		PlaceAt(New(report, "B2"))
+	if err != nil {
+		t.Fatalf("PlaceAt should succeed, but %s", err)
+	}
+	if report.Cell(1, 1).Value != "" {
+		t.Errorf("PlaceAt should not write static values, but %s", report.Cell(1, 1).Value)
+	}
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err)
+	}
+	cacheXML := parts["xl/pivotCache/pivotCacheDefinition1.xml"]
+	for _, text := range []string{
+		`saveData="0" refreshOnLoad="1"`,
+		`<worksheetSource ref="A1:C6" sheet="Data"/>`,
+		`<cacheFields count="3"><cacheField name="Status"`,
+	} {
+		if !strings.Contains(cacheXML, text) {
+			t.Errorf("pivot cache definition should contain %s, but %s", text, cacheXML)
+		}
+	}
+	tableXML := parts["xl/pivotTables/pivotTable1.xml"]
+	for _, text := range []string{
+		`name="Sales &amp; Status" cacheId="1"`,
+		`<location ref="B2:N6"`,
+		`<pivotField axis="axisRow" showAll="0"><items count="1"><item t="default"/></items></pivotField>`,
+		`<pivotField dataField="1" showAll="0"/>`,
+		`<rowFields count="1"><field x="0"/></rowFields>`,
+		`<colFields count="2"><field x="2"/><field x="-2"/></colFields>`,
+		`<dataField name="Sum(Amount)" fld="1" subtotal="sum" baseField="0" baseItem="0"/>`,
+		`<dataField name="CountA(Amount)" fld="1" subtotal="count"`,
+	} {
+		if !strings.Contains(tableXML, text) {
+			t.Errorf("pivot table definition should contain %s, but %s", text, tableXML)
+		}
+	}
+	if rels := parts["xl/worksheets/_rels/sheet2.xml.rels"]; !strings.Contains(rels, `Target="../pivotTables/pivotTable1.xml"`) {
+		t.Errorf("sheet relationships should have pivot table, but %s", rels)
+	}
+	if rels := parts["xl/pivotTables/_rels/pivotTable1.xml.rels"]; !strings.Contains(rels, `Target="../pivotCache/pivotCacheDefinition1.xml"`) {
+		t.Errorf("pivot table relationships should have pivot cache, but %s", rels)
+	}
+	workbookRels := parts["xl/_rels/workbook.xml.rels"]
+	if !strings.Contains(workbookRels, `Target="worksheets/sheet1.xml"`) ||
+		!strings.Contains(workbookRels, `<Relationship Id="rId6" Type="`+relationshipBase+`pivotCacheDefinition" Target="pivotCache/pivotCacheDefinition1.xml">`) {
+		t.Errorf("workbook relationships should keep existing ones and add pivot cache, but %s", workbookRels)
+	}
+	if workbook := parts["xl/workbook.xml"]; !strings.Contains(workbook, `<pivotCaches><pivotCache cacheId="1" r:id="rId6"/></pivotCaches></workbook>`) {
+		t.Errorf("workbook should have pivot cache, but %s", workbook)
+	}
+	if types := parts["[Content_Types].xml"]; !strings.Contains(types, `<Override PartName="/xl/pivotTables/pivotTable1.xml"`) {
+		t.Errorf("content types should have pivot table, but %s", types)
+	}
+
+	var buffer bytes.Buffer
+	if err := Write(file, &buffer); err != nil {
+		t.Fatalf("Write should succeed, but %s", err)
+	}
+	if _, err := xlsx.OpenBinary(buffer.Bytes()); err != nil {
+		t.Errorf("written file should be readable, but %s", err)
+	}
+}
+
+func TestPivotPlaceAtErrors(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	sum := AggregateSpec{Column: "Amount", Func: AggregateSum}
+	if err := New(sheet, "A1:C6").Pivot().Rows("Region").Values(sum).PlaceAt(New(sheet, "E1")); err == nil {
+		t.Errorf("PlaceAt should return error for unknown field")
+	}
+	if err := New(sheet, "A1:D6").Pivot().Rows("Status").Values(sum).PlaceAt(New(sheet, "F1")); err == nil {
+		t.Errorf("PlaceAt should return error for blank header")
+	}
+	named := AggregateSpec{Column: "Amount", Func: AggregateSum, Name: "Owner"}
+	if err := New(sheet, "A1:C6").Pivot().Rows("Status").Values(named).PlaceAt(New(sheet, "E1")); err == nil {
+		t.Errorf("PlaceAt should return error for value field name that is same as header")
+	}
+	if err := New(sheet, "A1:C6").Pivot().Rows("Status").Values(sum).PlaceAt(New(sheet, "E1")); err != nil {
+		t.Errorf("PlaceAt should succeed, but %s", err)
+	}
+	if err := New(sheet, "A1:C6").Pivot().Rows("Owner").Values(sum).Name("PivotTable1").PlaceAt(New(sheet, "E20")); err == nil {
+		t.Errorf("PlaceAt should return error for duplicated name")
+	}
+}
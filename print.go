@@ -0,0 +1,207 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	maxRowIndex    = 1048575 // Last row index (0 origin) of Excel
+	maxColumnIndex = 16383   // Last column index (0 origin) of Excel
+)
+
+// SetPrintArea sets print area of the sheet to selected range and more ranges in the same sheet.
+// Existing print area is replaced.
+//
+// Print settings are kept by this package because tealeg/xlsx doesn't write them.
+// Use xlsxrange.Save or xlsxrange.Write to write them into the file.
+func (r *Range) SetPrintArea(more ...*Range) error {
+	for _, other := range more {
+		if other.Sheet != r.Sheet {
+			return fmt.Errorf("Print area should be in sheet '%s', but %s", r.Sheet.Name, other.Format(true))
+		}
+	}
+	extensions.Lock()
+	defer extensions.Unlock()
+	sheetExtensionOf(r.Sheet).printArea = append([]*Range{r}, more...)
+	return nil
+}
+
+// ClearPrintArea removes print area of the sheet
+func (r *Range) ClearPrintArea() {
+	extensions.Lock()
+	defer extensions.Unlock()
+	if ext := findSheetExtension(r.Sheet); ext != nil {
+		ext.printArea = nil
+	}
+}
+
+// PrintArea returns ranges of print area of the sheet. It returns nil if print area is not set.
+func (r *Range) PrintArea() []*Range {
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext := findSheetExtension(r.Sheet)
+	if ext == nil || len(ext.printArea) == 0 {
+		return nil
+	}
+	return append([]*Range{}, ext.printArea...)
+}
+
+// SetPrintTitles sets rows or columns that are repeated on each printed page.
+// Selected range should be whole rows like "1:2" or whole columns like "A:A".
+// Title rows and title columns are kept separately, so call it twice to set both.
+// Like print area, they are written only by xlsxrange.Save or xlsxrange.Write.
+func (r *Range) SetPrintTitles() error {
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext := sheetExtensionOf(r.Sheet)
+	switch {
+	case r.NumRows != AllRows && r.NumColumns == AllColumns && r.Column == 1:
+		ext.printTitleRows = r
+	case r.NumRows == AllRows && r.NumColumns != AllColumns && r.Row == 1:
+		ext.printTitleColumns = r
+	default:
+		return fmt.Errorf("Print titles should be whole rows like 1:2 or whole columns like A:A, but %s", r.Format(false))
+	}
+	return nil
+}
+
+// ClearPrintTitles removes print titles of the sheet
+func (r *Range) ClearPrintTitles() {
+	extensions.Lock()
+	defer extensions.Unlock()
+	if ext := findSheetExtension(r.Sheet); ext != nil {
+		ext.printTitleRows = nil
+		ext.printTitleColumns = nil
+	}
+}
+
+// PrintTitles returns title rows and title columns of the sheet. They are nil if they are not set.
+func (r *Range) PrintTitles() (*Range, *Range) {
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext := findSheetExtension(r.Sheet)
+	if ext == nil {
+		return nil, nil
+	}
+	return ext.printTitleRows, ext.printTitleColumns
+}
+
+// AddPageBreaks adds manual page breaks at boundaries of selected range, so it starts on a new page
+// and following cells start on another page. Row breaks are not added for whole-column selections
+// and column breaks are not added for whole-row selections.
+// Page breaks are written only by xlsxrange.Save or xlsxrange.Write.
+func (r *Range) AddPageBreaks() {
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext := sheetExtensionOf(r.Sheet)
+	rowCount, columnCount := r.size()
+	// break ID is the number of rows or columns before the break
+	if r.NumRows != AllRows {
+		ext.rowBreaks = addBreaks(ext.rowBreaks, maxRowIndex, r.Row-1, r.Row+rowCount-1)
+	}
+	if r.NumColumns != AllColumns {
+		ext.columnBreaks = addBreaks(ext.columnBreaks, maxColumnIndex, r.Column-1, r.Column+columnCount-1)
+	}
+}
+
+// ClearPageBreaks removes all manual page breaks of the sheet
+func (r *Range) ClearPageBreaks() {
+	extensions.Lock()
+	defer extensions.Unlock()
+	if ext := findSheetExtension(r.Sheet); ext != nil {
+		ext.rowBreaks = nil
+		ext.columnBreaks = nil
+	}
+}
+
+// PageBreaks returns manual page breaks of the sheet. Each break is the row or column number
+// (1 origin) of the last row or column before the break.
+func (r *Range) PageBreaks() ([]int, []int) {
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext := findSheetExtension(r.Sheet)
+	if ext == nil {
+		return []int{}, []int{}
+	}
+	return append([]int{}, ext.rowBreaks...), append([]int{}, ext.columnBreaks...)
+}
+
+// addBreaks adds break IDs into sorted IDs. IDs out of the sheet are ignored.
+func addBreaks(breaks []int, limit int, ids ...int) []int {
+	for _, id := range ids {
+		if id <= 0 || id > limit || indexOfInt(breaks, id) != -1 {
+			continue
+		}
+		breaks = append(breaks, id)
+	}
+	sort.Ints(breaks)
+	return breaks
+}
+
+func indexOfInt(values []int, value int) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// pageBreaks returns rowBreaks and colBreaks elements of the sheet
+func pageBreaks(ext *sheetExtension) []string {
+	var children []string
+	for _, breaks := range []struct {
+		name string
+		ids  []int
+		max  int
+	}{
+		{"rowBreaks", ext.rowBreaks, maxColumnIndex},
+		{"colBreaks", ext.columnBreaks, maxRowIndex},
+	} {
+		if len(breaks.ids) == 0 {
+			continue
+		}
+		var buffer bytes.Buffer
+		fmt.Fprintf(&buffer, `<%s count="%d" manualBreakCount="%d">`, breaks.name, len(breaks.ids), len(breaks.ids))
+		for _, id := range breaks.ids {
+			fmt.Fprintf(&buffer, `<brk id="%d" max="%d" man="1"/>`, id, breaks.max)
+		}
+		fmt.Fprintf(&buffer, `</%s>`, breaks.name)
+		children = append(children, buffer.String())
+	}
+	return children
+}
+
+// addPrintNames adds defined names of print area and print titles of the sheet.
+// sheetIndex is the index (0 origin) of the sheet in the workbook.
+func (b *packageBuilder) addPrintNames(sheetIndex int, ext *sheetExtension) {
+	var references []string
+	for _, area := range ext.printArea {
+		references = append(references, referenceFormula(area))
+	}
+	if len(references) > 0 {
+		b.addDefinedName("_xlnm.Print_Area", sheetIndex, strings.Join(references, ","))
+	}
+	references = nil
+	// Excel writes title columns before title rows
+	for _, titles := range []*Range{ext.printTitleColumns, ext.printTitleRows} {
+		if titles != nil {
+			references = append(references, referenceFormula(titles))
+		}
+	}
+	if len(references) > 0 {
+		b.addDefinedName("_xlnm.Print_Titles", sheetIndex, strings.Join(references, ","))
+	}
+}
+
+func (b *packageBuilder) addDefinedName(name string, sheetIndex int, formula string) {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, `<definedName name="%s" localSheetId="%d">`, name, sheetIndex)
+	xml.EscapeText(&buffer, []byte(formula))
+	buffer.WriteString(`</definedName>`)
+	b.definedNames = append(b.definedNames, buffer.String())
+}
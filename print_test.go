@@ -0,0 +1,109 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestSetPrintArea(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 2"]
+	if err := New(sheet, "A1:D10").SetPrintArea(New(sheet, "F1:G5")); err != nil {
+		t.Fatalf("SetPrintArea should succeed, but %s", err.Error())
+	}
+	if err := New(sheet, "A1:D10").SetPrintArea(New(file.Sheet["Sheet 1"], "A1")); err == nil {
+		t.Errorf("print area in other sheet should be error")
+	}
+	if area := New(sheet).PrintArea(); len(area) != 2 || area[1].Format(false) != "F1:G5" {
+		t.Errorf("print area should be kept, but %d ranges", len(area))
+	}
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	expected := `<definedNames><definedName name="_xlnm.Print_Area" localSheetId="1">&#39;Sheet 2&#39;!$A$1:$D$10,&#39;Sheet 2&#39;!$F$1:$G$5</definedName></definedNames><calcPr`
+	if xml := parts["xl/workbook.xml"]; !strings.Contains(xml, expected) {
+		t.Errorf("workbook should have print area, but %s", xml)
+	}
+	New(sheet).ClearPrintArea()
+	if area := New(sheet).PrintArea(); len(area) != 0 {
+		t.Errorf("print area should be cleared, but %v", area)
+	}
+}
+
+func TestSetPrintTitles(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 1"]
+	if err := New(sheet, "1:2").SetPrintTitles(); err != nil {
+		t.Fatalf("SetPrintTitles should succeed, but %s", err.Error())
+	}
+	if err := New(sheet, "A:A").SetPrintTitles(); err != nil {
+		t.Fatalf("SetPrintTitles should succeed, but %s", err.Error())
+	}
+	if err := New(sheet, "A1:B2").SetPrintTitles(); err == nil {
+		t.Errorf("print titles that are not whole rows or columns should be error")
+	}
+	parts, _ := MarshallParts(file)
+	expected := `<definedName name="_xlnm.Print_Titles" localSheetId="0">&#39;Sheet 1&#39;!$A:$A,&#39;Sheet 1&#39;!$1:$2</definedName>`
+	if xml := parts["xl/workbook.xml"]; !strings.Contains(xml, expected) {
+		t.Errorf("workbook should have print titles, but %s", xml)
+	}
+
+	var buffer bytes.Buffer
+	if err := Write(file, &buffer); err != nil {
+		t.Fatalf("Write should succeed, but %s", err.Error())
+	}
+	written, err := xlsx.OpenBinary(buffer.Bytes())
+	if err != nil {
+		t.Fatalf("written file should be readable, but %s", err.Error())
+	}
+	if len(written.DefinedNames) != 1 || written.DefinedNames[0].Name != "_xlnm.Print_Titles" {
+		t.Errorf("written file should have print titles, but %d names", len(written.DefinedNames))
+	}
+}
+
+func TestAddPageBreaks(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 1"]
+	New(sheet, "A1:C5").AddPageBreaks()
+	New(sheet, "6:10").AddPageBreaks()
+	rows, columns := New(sheet).PageBreaks()
+	if len(rows) != 2 || rows[0] != 5 || rows[1] != 10 {
+		t.Errorf("row breaks should be [5 10], but %v", rows)
+	}
+	if len(columns) != 1 || columns[0] != 3 {
+		t.Errorf("column breaks should be [3], but %v", columns)
+	}
+	parts, _ := MarshallParts(file)
+	xml := parts["xl/worksheets/sheet1.xml"]
+	expected := `<rowBreaks count="2" manualBreakCount="2"><brk id="5" max="16383" man="1"/><brk id="10" max="16383" man="1"/></rowBreaks>` +
+		`<colBreaks count="1" manualBreakCount="1"><brk id="3" max="1048575" man="1"/></colBreaks>`
+	if !strings.Contains(xml, expected) || strings.Index(xml, "<headerFooter") > strings.Index(xml, "<rowBreaks") {
+		t.Errorf("sheet should have page breaks after headerFooter, but %s", xml)
+	}
+	New(sheet).ClearPageBreaks()
+	if rows, columns := New(sheet).PageBreaks(); len(rows) != 0 || len(columns) != 0 {
+		t.Errorf("page breaks should be cleared")
+	}
+}
+
+func TestPrintAccessorsDontRegisterFile(t *testing.T) {
+	file := createWritableFile()
+	aRange := New(file.Sheet["Sheet 1"], "A1:B3")
+	if aRange.PrintArea() != nil {
+		t.Errorf("PrintArea should be nil if it is not set")
+	}
+	aRange.PrintTitles()
+	if rowBreaks, columnBreaks := aRange.PageBreaks(); len(rowBreaks) != 0 || len(columnBreaks) != 0 {
+		t.Errorf("PageBreaks should be empty if they are not set, but %v, %v", rowBreaks, columnBreaks)
+	}
+	aRange.ClearPrintArea()
+	aRange.ClearPrintTitles()
+	aRange.ClearPageBreaks()
+	if registered(file) {
+		t.Errorf("read-only access should not register the file")
+	}
+}
@@ -1,3 +1,12 @@
+// Package xlsxrange provides range based operations (like A1 notation selection, import, export and formatting)
+// for spreadsheets of github.com/tealeg/xlsx.
+//
+// Some features can't be stored in xlsx.File because tealeg/xlsx doesn't support them: conditional formats,
+// tables, charts, pivot tables, hidden and collapsed rows, print areas, print titles and page breaks.
+// They are kept in a registry of this package for each xlsx.File and written only by Save, Write and
+// MarshallParts of this package.
+// xlsx.File.Save and xlsx.File.Write silently drop them. The registry keeps the file until Release is called,
+// so call Release when the file is not needed anymore in long-running processes.
 package xlsxrange
 
 import (
@@ -149,6 +158,24 @@ func (r *Range) GetCellAt(refRow, refCol int) *xlsx.Cell {
 
 // GetCells returns cells in selected range
 func (r *Range) GetCells() [][]*xlsx.Cell {
+	rowCount, columnCount := r.size()
+	rows := make([][]*xlsx.Cell, rowCount)
+	for rowIndex := 0; rowIndex < rowCount; rowIndex++ {
+		row := make([]*xlsx.Cell, columnCount)
+		rows[rowIndex] = row
+		srcRow := r.Sheet.Rows[rowIndex+r.Row-1]
+
+		for column := 0; column < columnCount; column++ {
+			absCol := column + r.Column - 1
+			row[column] = srcRow.Cells[absCol]
+		}
+	}
+	return rows
+}
+
+// size returns number of rows and columns in selected range.
+// AllRows and AllColumns are resolved by MaxRow and MaxCol of the sheet.
+func (r *Range) size() (int, int) {
 	rowCount := r.NumRows
 	if rowCount == AllRows {
 		rowCount = r.Sheet.MaxRow - r.Row + 1
@@ -156,22 +183,27 @@ func (r *Range) GetCells() [][]*xlsx.Cell {
 	if rowCount < 0 {
 		rowCount = 0
 	}
-	rows := make([][]*xlsx.Cell, rowCount)
 	columnCount := r.NumColumns
 	if columnCount == AllColumns {
 		columnCount = r.Sheet.MaxCol - r.Column + 1
 	}
-	for rowIndex := 0; rowIndex < rowCount; rowIndex++ {
-		row := make([]*xlsx.Cell, columnCount)
-		rows[rowIndex] = row
-		srcRow := r.Sheet.Rows[rowIndex+r.Row-1]
+	if columnCount < 0 {
+		columnCount = 0
+	}
+	return rowCount, columnCount
+}
 
-		for column := 0; column < columnCount; column++ {
-			absCol := column + r.Column - 1
-			row[column] = srcRow.Cells[absCol]
-		}
+// cellAt returns cell at absolute position (0 origin).
+// Unlike GetCellAt, it returns nil if the sheet doesn't have the cell.
+func (r *Range) cellAt(row, col int) *xlsx.Cell {
+	if row < 0 || row >= len(r.Sheet.Rows) || r.Sheet.Rows[row] == nil {
+		return nil
 	}
-	return rows
+	cells := r.Sheet.Rows[row].Cells
+	if col < 0 || col >= len(cells) {
+		return nil
+	}
+	return cells[col]
 }
 
 func (r *Range) String() string {
@@ -211,3 +243,17 @@ func (r *Range) Format(includeSheetName bool) string {
 
 	return buffer.String()
 }
+
+// copyCell copies value, formula and style of src into dst.
+// Merge information is not copied.
+func copyCell(dst, src *xlsx.Cell) {
+	row := dst.Row
+	if src == nil {
+		*dst = xlsx.Cell{}
+	} else {
+		*dst = *src
+	}
+	dst.Row = row
+	dst.HMerge = 0
+	dst.VMerge = 0
+}
@@ -0,0 +1,244 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tealeg/xlsx"
+)
+
+// RenderFormat specifies output format of Range.Render
+type RenderFormat int
+
+const (
+	Markdown RenderFormat = iota // GitHub Flavored Markdown table. First row is used as header.
+	HTML                         // HTML table with merged cells and basic styles
+	ASCII                        // Fixed width text grid with row numbers and column labels
+)
+
+// Render returns selected cells as a table text.
+//
+// Cell values are formatted by their number formats.
+func (r *Range) Render(format RenderFormat) string {
+	switch format {
+	case HTML:
+		return r.renderHTML()
+	case ASCII:
+		return r.renderASCII()
+	default:
+		return r.renderMarkdown()
+	}
+}
+
+func (r *Range) renderMarkdown() string {
+	texts := r.textMatrix(&ExportOptions{Formatted: true})
+	if len(texts) == 0 {
+		return ""
+	}
+	var buffer bytes.Buffer
+	writeRow := func(values []string) {
+		buffer.WriteByte('|')
+		for _, value := range values {
+			buffer.WriteByte(' ')
+			buffer.WriteString(escapeMarkdown(value))
+			buffer.WriteString(" |")
+		}
+		buffer.WriteByte('\n')
+	}
+	writeRow(texts[0])
+	buffer.WriteByte('|')
+	for range texts[0] {
+		buffer.WriteString(" --- |")
+	}
+	buffer.WriteByte('\n')
+	for _, row := range texts[1:] {
+		writeRow(row)
+	}
+	return buffer.String()
+}
+
+func escapeMarkdown(value string) string {
+	value = strings.Replace(value, "|", `\|`, -1)
+	value = strings.Replace(value, "\r\n", "<br>", -1)
+	return strings.Replace(value, "\n", "<br>", -1)
+}
+
+func (r *Range) renderHTML() string {
+	rowCount, columnCount := r.size()
+	opts := &ExportOptions{Formatted: true}
+	covered := make(map[[2]int]bool)
+	var buffer bytes.Buffer
+	buffer.WriteString("<table>\n")
+	for row := 0; row < rowCount; row++ {
+		buffer.WriteString("<tr>")
+		for column := 0; column < columnCount; column++ {
+			if covered[[2]int{row, column}] {
+				continue
+			}
+			cell := r.cellAt(r.Row+row-1, r.Column+column-1)
+			buffer.WriteString("<td")
+			if cell != nil {
+				rowSpan := min(cell.VMerge+1, rowCount-row)
+				colSpan := min(cell.HMerge+1, columnCount-column)
+				for i := 0; i < rowSpan; i++ {
+					for j := 0; j < colSpan; j++ {
+						covered[[2]int{row + i, column + j}] = true
+					}
+				}
+				if rowSpan > 1 {
+					fmt.Fprintf(&buffer, ` rowspan="%d"`, rowSpan)
+				}
+				if colSpan > 1 {
+					fmt.Fprintf(&buffer, ` colspan="%d"`, colSpan)
+				}
+				if css := styleToCSS(cellStyle(cell)); css != "" {
+					fmt.Fprintf(&buffer, ` style="%s"`, css)
+				}
+			}
+			buffer.WriteByte('>')
+			buffer.WriteString(strings.Replace(html.EscapeString(r.cellText(cell, opts)), "\n", "<br>", -1))
+			buffer.WriteString("</td>")
+		}
+		buffer.WriteString("</tr>\n")
+	}
+	buffer.WriteString("</table>\n")
+	return buffer.String()
+}
+
+// styleToCSS converts basic cell styles into CSS declarations
+func styleToCSS(style *xlsx.Style) string {
+	if style == nil {
+		return ""
+	}
+	var declarations []string
+	if style.Font.Bold {
+		declarations = append(declarations, "font-weight:bold")
+	}
+	if style.Font.Italic {
+		declarations = append(declarations, "font-style:italic")
+	}
+	if style.Font.Underline {
+		declarations = append(declarations, "text-decoration:underline")
+	}
+	if color := cssColor(style.Font.Color); color != "" {
+		declarations = append(declarations, "color:"+color)
+	}
+	if style.Fill.PatternType == xlsx.Solid_Cell_Fill {
+		if color := cssColor(style.Fill.FgColor); color != "" {
+			declarations = append(declarations, "background-color:"+color)
+		}
+	}
+	switch style.Alignment.Horizontal {
+	case "left", "center", "right", "justify":
+		declarations = append(declarations, "text-align:"+style.Alignment.Horizontal)
+	}
+	switch style.Alignment.Vertical {
+	case "top":
+		declarations = append(declarations, "vertical-align:top")
+	case "center":
+		declarations = append(declarations, "vertical-align:middle")
+	}
+	return strings.Join(declarations, ";")
+}
+
+// cssColor converts ARGB color ("FF336699") into CSS color ("#336699")
+func cssColor(argb string) string {
+	switch len(argb) {
+	case 8:
+		return "#" + argb[2:]
+	case 6:
+		return "#" + argb
+	}
+	return ""
+}
+
+func (r *Range) renderASCII() string {
+	texts := r.textMatrix(&ExportOptions{Formatted: true})
+	rowCount, columnCount := r.size()
+
+	widths := make([]int, columnCount+1)
+	widths[0] = len(strconv.Itoa(r.Row + rowCount - 1))
+	for column := 0; column < columnCount; column++ {
+		widths[column+1] = len(NumberToColumnStr(r.Column + column))
+	}
+	for _, row := range texts {
+		for column, text := range row {
+			if width := displayWidth(text); width > widths[column+1] {
+				widths[column+1] = width
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	writeBorder := func() {
+		buffer.WriteByte('+')
+		for _, width := range widths {
+			buffer.WriteString(strings.Repeat("-", width+2))
+			buffer.WriteByte('+')
+		}
+		buffer.WriteByte('\n')
+	}
+	writeRow := func(values []string, rightAligns []bool) {
+		buffer.WriteByte('|')
+		for i, value := range values {
+			padding := strings.Repeat(" ", widths[i]-displayWidth(value))
+			buffer.WriteByte(' ')
+			if rightAligns[i] {
+				buffer.WriteString(padding)
+				buffer.WriteString(value)
+			} else {
+				buffer.WriteString(value)
+				buffer.WriteString(padding)
+			}
+			buffer.WriteString(" |")
+		}
+		buffer.WriteByte('\n')
+	}
+
+	header := make([]string, columnCount+1)
+	rightAligns := make([]bool, columnCount+1)
+	for column := 0; column < columnCount; column++ {
+		header[column+1] = NumberToColumnStr(r.Column + column)
+	}
+	writeBorder()
+	writeRow(header, rightAligns)
+	writeBorder()
+	for row, values := range texts {
+		rightAligns[0] = true
+		for column := range values {
+			cell := r.cellAt(r.Row+row-1, r.Column+column-1)
+			rightAligns[column+1] = cell != nil && cell.Type() == xlsx.CellTypeNumeric
+		}
+		writeRow(append([]string{strconv.Itoa(r.Row + row)}, values...), rightAligns)
+	}
+	writeBorder()
+	return buffer.String()
+}
+
+// displayWidth returns width of text in fixed width font.
+// East Asian wide characters are counted as two columns.
+func displayWidth(text string) int {
+	width := 0
+	for _, c := range text {
+		if isWideRune(c) {
+			width += 2
+		} else if c != utf8.RuneError {
+			width++
+		}
+	}
+	return width
+}
+
+func isWideRune(c rune) bool {
+	return (c >= 0x1100 && c <= 0x115F) ||
+		(c >= 0x2E80 && c <= 0xA4CF) ||
+		(c >= 0xAC00 && c <= 0xD7A3) ||
+		(c >= 0xF900 && c <= 0xFAFF) ||
+		(c >= 0xFE30 && c <= 0xFE4F) ||
+		(c >= 0xFF00 && c <= 0xFF60) ||
+		(c >= 0xFFE0 && c <= 0xFFE6)
+}
@@ -0,0 +1,63 @@
+package xlsxrange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	file := createFile()
+	file.Sheet["Sheet 1"].Rows[2].Cells[2].SetString("a|b")
+	aRange := New(file.Sheet["Sheet 1"], "B2:C3")
+
+	expected := "| B2 | C2 |\n| --- | --- |\n| B3 | a\\|b |\n"
+	if aRange.Render(Markdown) != expected {
+		t.Errorf("Render(Markdown) should return '%s', but '%s'", expected, aRange.Render(Markdown))
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[1].Cells[1].Merge(1, 0)
+	style := xlsx.NewStyle()
+	style.Font.Bold = true
+	sheet.Rows[2].Cells[1].SetStyle(style)
+	sheet.Rows[2].Cells[2].SetString("<x>")
+	aRange := New(sheet, "B2:C3")
+
+	result := aRange.Render(HTML)
+	if !strings.Contains(result, `<td colspan="2">B2</td></tr>`) {
+		t.Errorf("Render(HTML) should contain colspan cell, but '%s'", result)
+	}
+	if !strings.Contains(result, `<td style="font-weight:bold">B3</td>`) {
+		t.Errorf("Render(HTML) should contain styled cell, but '%s'", result)
+	}
+	if !strings.Contains(result, `&lt;x&gt;`) {
+		t.Errorf("Render(HTML) should escape cell value, but '%s'", result)
+	}
+	if cellStyle(sheet.Rows[2].Cells[2]) != nil {
+		t.Errorf("Render(HTML) should not add style to cells")
+	}
+}
+
+func TestRenderASCII(t *testing.T) {
+	file := createFile()
+	file.Sheet["Sheet 1"].Rows[9].Cells[2].SetInt(5)
+	aRange := New(file.Sheet["Sheet 1"], "B9:C10")
+
+	expected := strings.Join([]string{
+		"+----+-----+----+",
+		"|    | B   | C  |",
+		"+----+-----+----+",
+		"|  9 | B9  | C9 |",
+		"| 10 | B10 |  5 |",
+		"+----+-----+----+",
+		"",
+	}, "\n")
+	if aRange.Render(ASCII) != expected {
+		t.Errorf("Render(ASCII) should return\n%s\nbut\n%s", expected, aRange.Render(ASCII))
+	}
+}
@@ -0,0 +1,106 @@
+package xlsxrange
+
+import (
+	"errors"
+
+	"github.com/tealeg/xlsx"
+)
+
+// IterateOptions controls which rows are visited by row iteration.
+// nil is acceptable and it means zero value of this struct.
+type IterateOptions struct {
+	SkipHidden        bool // Skips hidden rows
+	SkipHiddenColumns bool // Excludes hidden columns from cells of each row
+	SkipCollapsed     bool // Skips rows and columns hidden by collapsed outline groups
+	SkipEmpty         bool // Skips rows whose cells in selected range are all empty
+}
+
+// RowCursor walks rows of selected range one by one.
+//
+//	rows := aRange.Rows(nil)
+//	for rows.Next() {
+//		cells := rows.Row()
+//	}
+//	if err := rows.Err(); err != nil {
+//	}
+type RowCursor struct {
+	r           *Range
+	opts        IterateOptions
+	rowCount    int
+	columnCount int
+	index       int
+	columns     []int
+	row         []*xlsx.Cell
+	err         error
+}
+
+// Rows returns cursor to iterate rows in selected range lazily.
+//
+// Unlike GetCells, it doesn't allocate whole cells at once.
+func (r *Range) Rows(opts *IterateOptions) *RowCursor {
+	cursor := &RowCursor{
+		r:     r,
+		index: -1,
+	}
+	if opts != nil {
+		cursor.opts = *opts
+	}
+	if r.Sheet == nil {
+		cursor.err = errors.New("Sheet is not selected")
+		return cursor
+	}
+	cursor.rowCount, cursor.columnCount = r.size()
+	cursor.columns = r.visibleColumns(&cursor.opts)
+	cursor.row = make([]*xlsx.Cell, len(cursor.columns))
+	return cursor
+}
+
+// Next moves cursor to next row. It returns false when there are no more rows.
+func (c *RowCursor) Next() bool {
+	if c.err != nil {
+		return false
+	}
+	for c.index+1 < c.rowCount {
+		c.index++
+		absRow := c.r.Row + c.index - 1
+		if c.r.skipRow(absRow+1, &c.opts) {
+			continue
+		}
+		empty := true
+		for i, column := range c.columns {
+			cell := c.r.cellAt(absRow, column-1)
+			c.row[i] = cell
+			if cell != nil && (cell.Value != "" || cell.Formula() != "") {
+				empty = false
+			}
+		}
+		if c.opts.SkipEmpty && empty {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// Row returns cells of current row.
+//
+// The returned slice is reused by next call of Next. Missing cells are nil.
+// Columns skipped by SkipHiddenColumns or SkipCollapsed are not included.
+func (c *RowCursor) Row() []*xlsx.Cell {
+	return c.row
+}
+
+// ColumnNumbers returns column numbers (1 origin) in the sheet of cells returned by Row.
+func (c *RowCursor) ColumnNumbers() []int {
+	return c.columns
+}
+
+// RowNumber returns row number (1 origin) of current row in the sheet.
+func (c *RowCursor) RowNumber() int {
+	return c.r.Row + c.index
+}
+
+// Err returns error occurred during iteration.
+func (c *RowCursor) Err() error {
+	return c.err
+}
@@ -0,0 +1,27 @@
+//go:build go1.23
+
+package xlsxrange
+
+import (
+	"iter"
+
+	"github.com/tealeg/xlsx"
+)
+
+// RowSeq returns iterator of rows in selected range.
+// Keys are row numbers (1 origin) in the sheet.
+//
+//	for rowNumber, cells := range aRange.RowSeq(nil) {
+//	}
+//
+// The cell slice is reused between iterations as same as RowCursor.Row.
+func (r *Range) RowSeq(opts *IterateOptions) iter.Seq2[int, []*xlsx.Cell] {
+	return func(yield func(int, []*xlsx.Cell) bool) {
+		cursor := r.Rows(opts)
+		for cursor.Next() {
+			if !yield(cursor.RowNumber(), cursor.Row()) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package xlsxrange
+
+import "testing"
+
+func TestRows(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "B2:C4")
+
+	rows := aRange.Rows(nil)
+	count := 0
+	for rows.Next() {
+		count++
+		if len(rows.Row()) != 2 {
+			t.Errorf("row should have 2 cells, but %d", len(rows.Row()))
+		}
+	}
+	if rows.Err() != nil {
+		t.Errorf("Err() should be nil, but %s", rows.Err())
+	}
+	if count != 3 {
+		t.Errorf("row count should be 3, but %d", count)
+	}
+}
+
+func TestRowsWithAllRows(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "B:C")
+
+	rows := aRange.Rows(nil)
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	if count != 15 {
+		t.Errorf("row count should be 15, but %d", count)
+	}
+}
+
+func TestRowsWithSkip(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[2].Hidden = true
+	sheet.Rows[3].Cells[1].SetString("")
+	sheet.Rows[3].Cells[2].SetString("")
+	aRange := New(sheet, "B2:C5")
+
+	rows := aRange.Rows(&IterateOptions{SkipHidden: true, SkipEmpty: true})
+	var rowNumbers []int
+	for rows.Next() {
+		rowNumbers = append(rowNumbers, rows.RowNumber())
+	}
+	if len(rowNumbers) != 2 || rowNumbers[0] != 2 || rowNumbers[1] != 5 {
+		t.Errorf("visited rows should be [2 5], but %v", rowNumbers)
+	}
+}
+
+func TestRowsWithHiddenColumns(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	New(sheet, "C:C").HideColumns()
+	New(sheet, "3:3").GroupRows(true)
+	New(sheet, "4:4").HideRows()
+	aRange := New(sheet, "B2:D5")
+
+	rows := aRange.Rows(&IterateOptions{SkipHiddenColumns: true, SkipCollapsed: true})
+	var rowNumbers []int
+	for rows.Next() {
+		rowNumbers = append(rowNumbers, rows.RowNumber())
+		if cells := rows.Row(); len(cells) != 2 || cells[1].Value[0] != 'D' {
+			t.Errorf("hidden column C should be skipped, but %d cells", len(cells))
+		}
+	}
+	if len(rowNumbers) != 3 || rowNumbers[1] != 4 {
+		t.Errorf("only collapsed row 3 should be skipped, but %v", rowNumbers)
+	}
+	if columns := rows.ColumnNumbers(); len(columns) != 2 || columns[0] != 2 || columns[1] != 4 {
+		t.Errorf("column numbers should be [2 4], but %v", columns)
+	}
+	if cells := aRange.GetVisibleCells(&IterateOptions{SkipHidden: true}); len(cells) != 2 || len(cells[0]) != 3 {
+		t.Errorf("GetVisibleCells should skip hidden rows only, but %d rows", len(cells))
+	}
+}
+
+func TestRowSeq(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "B2:C4")
+
+	for rowNumber, cells := range aRange.RowSeq(nil) {
+		if rowNumber != 2 {
+			t.Errorf("first row number should be 2, but %d", rowNumber)
+		}
+		if cells[1].Value != "C2" {
+			t.Errorf("cells[1] should be 'C2', but %s", cells[1].Value)
+		}
+		break
+	}
+}
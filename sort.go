@@ -0,0 +1,137 @@
+package xlsxrange
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tealeg/xlsx"
+)
+
+// SortKey specifies a key column of Range.Sort
+type SortKey struct {
+	Column     int  // Column index relative from left of selected range (0 origin)
+	Descending bool // Sorts in descending order
+}
+
+// Sort sorts rows in selected range by key columns.
+//
+// It compares values like Excel: numbers, texts (case insensitive), booleans and errors in this order,
+// and blank cells are always placed last. Sort is stable.
+// Whole cells (value, style, formula) are moved within the sheet. Formula references are not adjusted.
+// It returns error if sorted rows contain merged cells.
+func (r *Range) Sort(keys ...SortKey) error {
+	return r.sortRows(0, keys)
+}
+
+// SortWithHeader is same as Sort, but the first row is treated as a header row and kept in place.
+func (r *Range) SortWithHeader(keys ...SortKey) error {
+	return r.sortRows(1, keys)
+}
+
+func (r *Range) sortRows(skip int, keys []SortKey) error {
+	rowCount, columnCount := r.size()
+	if len(keys) == 0 {
+		keys = []SortKey{{Column: 0}}
+	}
+	for _, key := range keys {
+		if key.Column < 0 || key.Column >= columnCount {
+			return fmt.Errorf("Sort key column %d is out of range (0 - %d)", key.Column, columnCount-1)
+		}
+	}
+	if rowCount-skip < 2 {
+		return nil
+	}
+	// merged areas can't follow moved rows, so Excel also refuses to sort them
+	sorted := New(r.Sheet, r.Row+skip, r.Column, rowCount-skip, columnCount)
+	if merges := sorted.Merges(); len(merges) > 0 {
+		return fmt.Errorf("Range %s contains merged cells %s", sorted.Format(false), merges[0].Format(false))
+	}
+	rows := make([][]*xlsx.Cell, rowCount-skip)
+	for i := range rows {
+		row := make([]*xlsx.Cell, columnCount)
+		for column := range row {
+			row[column] = r.Sheet.Cell(r.Row+skip+i-1, r.Column+column-1)
+		}
+		rows[i] = row
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, key := range keys {
+			result := compareCells(rows[i][key.Column], rows[j][key.Column], key.Descending)
+			if result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	})
+	for i, row := range rows {
+		sheetRow := r.Sheet.Rows[r.Row+skip+i-1]
+		for column, cell := range row {
+			cell.Row = sheetRow
+			sheetRow.Cells[r.Column+column-1] = cell
+		}
+	}
+	return nil
+}
+
+// Value kinds in Excel sort order
+const (
+	kindNumber = iota
+	kindText
+	kindBool
+	kindError
+	kindBlank
+)
+
+func cellKind(cell *xlsx.Cell) int {
+	if cell == nil || (cell.Value == "" && cell.Formula() == "") {
+		return kindBlank
+	}
+	switch cell.Type() {
+	case xlsx.CellTypeNumeric:
+		if _, err := strconv.ParseFloat(cell.Value, 64); err == nil {
+			return kindNumber
+		}
+	case xlsx.CellTypeBool:
+		return kindBool
+	case xlsx.CellTypeError:
+		return kindError
+	}
+	return kindText
+}
+
+// compareCells compares two cells like Excel. Blank cells are always last regardless of descending flag.
+func compareCells(a, b *xlsx.Cell, descending bool) int {
+	kindA := cellKind(a)
+	kindB := cellKind(b)
+	if kindA == kindBlank || kindB == kindBlank {
+		if kindA == kindB {
+			return 0
+		} else if kindA == kindBlank {
+			return 1
+		}
+		return -1
+	}
+	result := 0
+	if kindA != kindB {
+		result = kindA - kindB
+	} else {
+		switch kindA {
+		case kindNumber:
+			numberA, _ := strconv.ParseFloat(a.Value, 64)
+			numberB, _ := strconv.ParseFloat(b.Value, 64)
+			if numberA < numberB {
+				result = -1
+			} else if numberA > numberB {
+				result = 1
+			}
+		default:
+			result = strings.Compare(strings.ToLower(a.Value), strings.ToLower(b.Value))
+		}
+	}
+	if descending {
+		return -result
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package xlsxrange
+
+import "testing"
+
+func TestSort(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[0].Cells[0].SetString("b")
+	sheet.Rows[1].Cells[0].SetString("")
+	sheet.Rows[2].Cells[0].SetInt(10)
+	sheet.Rows[3].Cells[0].SetString("A")
+	sheet.Rows[4].Cells[0].SetInt(2)
+	aRange := New(sheet, "A1:B5")
+
+	err := aRange.Sort(SortKey{Column: 0})
+	if err != nil {
+		t.Errorf("Sort should not return error, but %s", err)
+	}
+	expected := []string{"2", "10", "A", "b", ""}
+	for i, value := range expected {
+		if aRange.GetCellAt(i, 0).Value != value {
+			t.Errorf("A%d should be '%s', but '%s'", i+1, value, aRange.GetCellAt(i, 0).Value)
+		}
+	}
+	if aRange.GetCellAt(0, 1).Value != "B5" {
+		t.Errorf("B1 should be moved from B5, but '%s'", aRange.GetCellAt(0, 1).Value)
+	}
+	if aRange.GetCellAt(0, 0).Row != sheet.Rows[0] {
+		t.Errorf("moved cell should refer new row")
+	}
+}
+
+func TestSortDescendingWithHeader(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[1].Cells[0].SetString("")
+	sheet.Rows[2].Cells[0].SetInt(1)
+	sheet.Rows[3].Cells[0].SetInt(3)
+	sheet.Rows[2].Cells[1].SetString("x")
+	sheet.Rows[3].Cells[1].SetString("x")
+	sheet.Rows[1].Cells[1].SetString("x")
+	aRange := New(sheet, "A1:B4")
+
+	aRange.SortWithHeader(SortKey{Column: 1}, SortKey{Column: 0, Descending: true})
+	expected := []string{"A1", "3", "1", ""}
+	for i, value := range expected {
+		if aRange.GetCellAt(i, 0).Value != value {
+			t.Errorf("A%d should be '%s', but '%s'", i+1, value, aRange.GetCellAt(i, 0).Value)
+		}
+	}
+}
+
+func TestSortWithInvalidKey(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "A1:B4")
+
+	if aRange.Sort(SortKey{Column: 2}) == nil {
+		t.Errorf("Sort should return error for out of range key")
+	}
+}
+
+func TestSortWithMergedCells(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	New(sheet, "A3:B3").Merge()
+	aRange := New(sheet, "A1:B4")
+
+	if aRange.Sort(SortKey{Column: 0, Descending: true}) == nil {
+		t.Errorf("Sort should return error for merged cells")
+	}
+	if aRange.GetCellAt(0, 0).Value != "A1" || aRange.GetCellAt(2, 0).HMerge != 1 {
+		t.Errorf("rows should not be moved when Sort fails")
+	}
+	if err := New(sheet, "A3:B5").SortWithHeader(SortKey{Column: 0, Descending: true}); err != nil {
+		t.Errorf("merged header row should be kept, but %s", err.Error())
+	}
+}
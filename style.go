@@ -0,0 +1,150 @@
+package xlsxrange
+
+import (
+	"github.com/tealeg/xlsx"
+)
+
+// SetStyle sets the style to all cells in selected range. All cells share the style object.
+func (r *Range) SetStyle(style *xlsx.Style) {
+	r.eachCell(func(cell *xlsx.Cell, row, column int) {
+		cell.SetStyle(style)
+	})
+}
+
+// SetFont sets font of all cells in selected range keeping other style attributes.
+func (r *Range) SetFont(font xlsx.Font) {
+	r.updateStyles(nil, func(style *xlsx.Style, variant int) {
+		style.Font = font
+		style.ApplyFont = true
+	})
+}
+
+// SetFill sets fill of all cells in selected range keeping other style attributes.
+func (r *Range) SetFill(fill xlsx.Fill) {
+	r.updateStyles(nil, func(style *xlsx.Style, variant int) {
+		style.Fill = fill
+		style.ApplyFill = true
+	})
+}
+
+// SetBorder sets border of all cells in selected range keeping other style attributes.
+func (r *Range) SetBorder(border xlsx.Border) {
+	r.updateStyles(nil, func(style *xlsx.Style, variant int) {
+		style.Border = border
+		style.ApplyBorder = true
+	})
+}
+
+// Border edges used by SetOutlineBorder
+const (
+	edgeLeft = 1 << iota
+	edgeRight
+	edgeTop
+	edgeBottom
+)
+
+// SetOutlineBorder draws border around the perimeter of selected range.
+// Inner borders are kept. lineStyle is Excel border style like "thin", "medium" or "double",
+// and color is ARGB color like "FF000000".
+func (r *Range) SetOutlineBorder(lineStyle, color string) {
+	rowCount, columnCount := r.size()
+	edges := func(row, column int) int {
+		variant := 0
+		if column == 0 {
+			variant |= edgeLeft
+		}
+		if column == columnCount-1 {
+			variant |= edgeRight
+		}
+		if row == 0 {
+			variant |= edgeTop
+		}
+		if row == rowCount-1 {
+			variant |= edgeBottom
+		}
+		return variant
+	}
+	r.updateStyles(edges, func(style *xlsx.Style, variant int) {
+		if variant&edgeLeft != 0 {
+			style.Border.Left = lineStyle
+			style.Border.LeftColor = color
+		}
+		if variant&edgeRight != 0 {
+			style.Border.Right = lineStyle
+			style.Border.RightColor = color
+		}
+		if variant&edgeTop != 0 {
+			style.Border.Top = lineStyle
+			style.Border.TopColor = color
+		}
+		if variant&edgeBottom != 0 {
+			style.Border.Bottom = lineStyle
+			style.Border.BottomColor = color
+		}
+		style.ApplyBorder = true
+	})
+}
+
+// SetAlignment sets alignment of all cells in selected range keeping other style attributes.
+func (r *Range) SetAlignment(alignment xlsx.Alignment) {
+	r.updateStyles(nil, func(style *xlsx.Style, variant int) {
+		style.Alignment = alignment
+		style.ApplyAlignment = true
+	})
+}
+
+// SetNumberFormat sets number format (like "#,##0.00" or "yyyy-mm-dd") of all cells in selected range.
+func (r *Range) SetNumberFormat(format string) {
+	r.eachCell(func(cell *xlsx.Cell, row, column int) {
+		cell.NumFmt = format
+	})
+}
+
+// eachCell calls f with all cells in selected range. Missing cells are created.
+// row and column are relative position (0 origin).
+func (r *Range) eachCell(f func(cell *xlsx.Cell, row, column int)) {
+	rowCount, columnCount := r.size()
+	for row := 0; row < rowCount; row++ {
+		for column := 0; column < columnCount; column++ {
+			f(r.Sheet.Cell(r.Row+row-1, r.Column+column-1), row, column)
+		}
+	}
+}
+
+// updateStyles modifies styles of all cells in selected range.
+//
+// Cells that have equal styles (and same variant) share the modified style object.
+// variant can return different values for each position to modify styles differently. It can be nil.
+func (r *Range) updateStyles(variant func(row, column int) int, modify func(style *xlsx.Style, variant int)) {
+	type styleKey struct {
+		style   xlsx.Style
+		variant int
+	}
+	cache := make(map[styleKey]*xlsx.Style)
+	r.eachCell(func(cell *xlsx.Cell, row, column int) {
+		key := styleKey{style: *cell.GetStyle()}
+		if variant != nil {
+			key.variant = variant(row, column)
+		}
+		style, ok := cache[key]
+		if !ok {
+			copied := key.style
+			modify(&copied, key.variant)
+			style = &copied
+			cache[key] = style
+		}
+		cell.SetStyle(style)
+	})
+}
+
+// cellStyle returns style of the cell or nil if the cell doesn't have style or has the default style.
+// Unlike xlsx.Cell.GetStyle, it doesn't allocate new style, so it is safe for read-only operations.
+func cellStyle(cell *xlsx.Cell) *xlsx.Style {
+	// tealeg/xlsx doesn't have accessor that doesn't allocate, so style of a copy is compared with the default
+	copied := *cell
+	style := copied.GetStyle()
+	if *style == *xlsx.NewStyle() {
+		return nil
+	}
+	return style
+}
@@ -0,0 +1,80 @@
+package xlsxrange
+
+import (
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestSetFont(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	aRange := New(sheet, "B2:C3")
+	aRange.SetFill(*xlsx.NewFill(xlsx.Solid_Cell_Fill, xlsx.RGB_Light_Red, xlsx.RGB_Light_Red))
+
+	font := *xlsx.NewFont(14, xlsx.Helvetica)
+	font.Bold = true
+	aRange.SetFont(font)
+
+	style := aRange.GetCellAt(1, 1).GetStyle()
+	if !style.Font.Bold || style.Font.Size != 14 {
+		t.Errorf("font should be changed")
+	}
+	if style.Fill.FgColor != xlsx.RGB_Light_Red {
+		t.Errorf("fill should be kept")
+	}
+	if aRange.GetCellAt(0, 0).GetStyle() != style {
+		t.Errorf("cells with same style should share style object")
+	}
+	if sheet.Rows[0].Cells[0].GetStyle().Font.Bold {
+		t.Errorf("cells out of range should not be changed")
+	}
+}
+
+func TestSetOutlineBorder(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "B2:D4")
+	aRange.SetOutlineBorder("thin", "FF000000")
+
+	leftTop := aRange.GetCellAt(0, 0).GetStyle().Border
+	if leftTop.Left != "thin" || leftTop.Top != "thin" || leftTop.Right != "none" || leftTop.Bottom != "none" {
+		t.Errorf("left top cell should have left and top border, but %v", leftTop)
+	}
+	center := aRange.GetCellAt(1, 1).GetStyle().Border
+	if center.Left != "none" || center.Top != "none" || center.Right != "none" || center.Bottom != "none" {
+		t.Errorf("center cell should not have border, but %v", center)
+	}
+	rightBottom := aRange.GetCellAt(2, 2).GetStyle().Border
+	if rightBottom.Right != "thin" || rightBottom.Bottom != "thin" {
+		t.Errorf("right bottom cell should have right and bottom border, but %v", rightBottom)
+	}
+}
+
+func TestSetNumberFormatAndAlignment(t *testing.T) {
+	file := createFile()
+	aRange := New(file.Sheet["Sheet 1"], "B2:C3")
+	aRange.SetNumberFormat("#,##0")
+	aRange.SetAlignment(xlsx.Alignment{Horizontal: "center", Vertical: "center"})
+
+	if aRange.GetCellAt(1, 1).NumFmt != "#,##0" {
+		t.Errorf("number format should be '#,##0', but %s", aRange.GetCellAt(1, 1).NumFmt)
+	}
+	if aRange.GetCellAt(1, 1).GetStyle().Alignment.Horizontal != "center" {
+		t.Errorf("alignment should be changed")
+	}
+}
+
+func TestCellStyle(t *testing.T) {
+	cell := &xlsx.Cell{}
+	if cellStyle(cell) != nil {
+		t.Errorf("cell without style should not have style")
+	}
+	cell.GetStyle()
+	if cellStyle(cell) != nil {
+		t.Errorf("cell with default style should be treated as no style")
+	}
+	cell.GetStyle().Font.Bold = true
+	if style := cellStyle(cell); style == nil || !style.Font.Bold {
+		t.Errorf("cell with style should return its style, but %v", style)
+	}
+}
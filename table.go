@@ -0,0 +1,386 @@
+package xlsxrange
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tealeg/xlsx"
+)
+
+// TableOptions controls Range.AsTable.
+// nil means default options (TableStyleMedium2 with banded rows).
+type TableOptions struct {
+	Style         string // Table style name like "TableStyleLight9". Default is "TableStyleMedium2".
+	BandedRows    bool
+	BandedColumns bool
+	FirstColumn   bool // Emphasizes the first column
+	LastColumn    bool // Emphasizes the last column
+
+	TotalsRow   bool                     // Adds totals row below data rows
+	TotalsLabel string                   // Label of the first column in totals row. Default is "Total".
+	Totals      map[string]AggregateFunc // Functions of totals row by column name
+}
+
+// Table is an Excel table (ListObject). Range is header row and data rows without totals row.
+//
+// Header names and totals row are written into cells by AsTable and Resize. Without totals row,
+// the range is read when the file is written, so using AllRows follows the used area.
+type Table struct {
+	Name    string
+	Range   *Range
+	Options TableOptions
+
+	totalsRow int // Row number (1 origin) of totals row written by AsTable or Resize
+}
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_\\][A-Za-z0-9_.]*$`)
+
+// AsTable makes selected range an Excel table. The first row is used as header row and header names
+// should be unique. Blank header cells are filled with names like "Column3".
+// Totals row is written into the row below selected range, and it should be empty.
+//
+// Tables are kept by this package because tealeg/xlsx doesn't support tables.
+// Use xlsxrange.Save or xlsxrange.Write to write them into the file.
+func (r *Range) AsTable(name string, opts *TableOptions) (*Table, error) {
+	if opts == nil {
+		opts = &TableOptions{BandedRows: true}
+	}
+	if !tableNamePattern.MatchString(name) || cellLikeSheetNamePattern.MatchString(name) || len(name) > 255 ||
+		strings.EqualFold(name, "R") || strings.EqualFold(name, "C") {
+		return nil, fmt.Errorf("Table name '%s' is invalid", name)
+	}
+	table := &Table{Name: name, Range: r, Options: *opts}
+	if err := table.validate(); err != nil {
+		return nil, err
+	}
+	area := table.area()
+	if opts.TotalsRow {
+		area.NumRows++
+	}
+	extensions.Lock()
+	defer extensions.Unlock()
+	if file, ok := extensions.files[r.Sheet.File]; ok {
+		for sheet, ext := range file.sheets {
+			for _, other := range ext.tables {
+				if strings.EqualFold(other.Name, name) {
+					return nil, fmt.Errorf("Table name '%s' is already used", name)
+				}
+				if sheet == r.Sheet && other.area().intersects(area) {
+					return nil, fmt.Errorf("Table '%s' overlaps table '%s'", name, other.Name)
+				}
+			}
+		}
+	}
+	if filter := r.AutoFilter(); filter != nil && filter.intersects(area) {
+		return nil, fmt.Errorf("Table '%s' overlaps autofilter of the sheet", name)
+	}
+	if err := table.writeCells(); err != nil {
+		return nil, err
+	}
+	ext := sheetExtensionOf(r.Sheet)
+	ext.tables = append(ext.tables, table)
+	return table, nil
+}
+
+// Tables returns tables that intersect selected range
+func (r *Range) Tables() []*Table {
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext := findSheetExtension(r.Sheet)
+	if ext == nil {
+		return nil
+	}
+	var result []*Table
+	for _, table := range ext.tables {
+		if r.intersects(table.area()) {
+			result = append(result, table)
+		}
+	}
+	return result
+}
+
+// Resize changes range of the table. Header names are validated and written again, and totals row
+// is moved below the new range. It returns error if the new totals row is not empty.
+func (t *Table) Resize(r *Range) error {
+	resized := &Table{Name: t.Name, Range: r, Options: t.Options}
+	if err := resized.validate(); err != nil {
+		return err
+	}
+	// old totals row is cleared first because it may be included in the new range
+	var saved []xlsx.Cell
+	old := t.totalsArea()
+	if old != nil {
+		for column := 0; column < old.NumColumns; column++ {
+			cell := old.Sheet.Cell(old.Row-1, old.Column+column-1)
+			saved = append(saved, *cell)
+			copyCell(cell, nil)
+		}
+	}
+	if err := resized.writeCells(); err != nil {
+		for column, cell := range saved {
+			*old.Sheet.Cell(old.Row-1, old.Column+column-1) = cell
+		}
+		return err
+	}
+	t.Range = r
+	t.totalsRow = resized.totalsRow
+	return nil
+}
+
+// Remove removes the table. Cells are kept.
+func (t *Table) Remove() {
+	extensions.Lock()
+	defer extensions.Unlock()
+	file, ok := extensions.files[t.Range.Sheet.File]
+	if !ok {
+		return
+	}
+	for _, ext := range file.sheets {
+		for i, table := range ext.tables {
+			if table == t {
+				ext.tables = append(ext.tables[:i], ext.tables[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Ref returns reference of the table including totals row like "A1:C7"
+func (t *Table) Ref() string {
+	return t.area().Format(false)
+}
+
+// Header returns column names of the table
+func (t *Table) Header() []string {
+	header := t.dataArea().header()
+	for i, name := range header {
+		if name == "" {
+			header[i] = fmt.Sprintf("Column%d", i+1)
+		}
+	}
+	return header
+}
+
+// dataArea returns header and data rows. Header row and at least one data row are included.
+func (t *Table) dataArea() *Range {
+	area := t.Range.clamp()
+	rowCount, columnCount := area.size()
+	if t.totalsRow != 0 {
+		// data rows end above totals row even if AllRows covers rows below it
+		rowCount = t.totalsRow - area.Row
+	}
+	return New(area.Sheet, area.Row, area.Column, max(rowCount, 2), max(columnCount, 1))
+}
+
+// area returns whole area of the table including totals row
+func (t *Table) area() *Range {
+	area := t.dataArea()
+	if t.totalsRow != 0 {
+		area.NumRows++
+	}
+	return area
+}
+
+// totalsArea returns totals row of the table or nil if the table doesn't have totals row
+func (t *Table) totalsArea() *Range {
+	if t.totalsRow == 0 {
+		return nil
+	}
+	area := t.dataArea()
+	return New(area.Sheet, t.totalsRow, area.Column, 1, area.NumColumns)
+}
+
+func (t *Table) validate() error {
+	header := t.Header()
+	seen := make(map[string]bool)
+	for _, name := range header {
+		key := strings.ToLower(name)
+		if seen[key] {
+			return fmt.Errorf("Header name '%s' of table '%s' is duplicated", name, t.Name)
+		}
+		seen[key] = true
+	}
+	for name := range t.Options.Totals {
+		if indexOf(header, name) == -1 {
+			return fmt.Errorf("Totals column '%s' is not found in header of table '%s'", name, t.Name)
+		}
+	}
+	return nil
+}
+
+var totalsFunctions = map[AggregateFunc]struct {
+	name string
+	code int
+}{
+	AggregateSum:     {"sum", 109},
+	AggregateAverage: {"average", 101},
+	AggregateMin:     {"min", 105},
+	AggregateMax:     {"max", 104},
+	AggregateCount:   {"countNums", 102},
+	AggregateCountA:  {"count", 103},
+}
+
+// writeCells writes header names and totals row into cells.
+// Cells are not changed if totals row is not empty.
+func (t *Table) writeCells() error {
+	area := t.dataArea()
+	header := t.Header()
+	totalsRow := 0
+	if t.Options.TotalsRow {
+		totalsRow = area.Row + area.NumRows
+		for i := range header {
+			if cellKind(area.cellAt(totalsRow-1, area.Column+i-1)) != kindBlank {
+				return fmt.Errorf("Totals row of table '%s' should be empty, but %s has value", t.Name, cellName(totalsRow, area.Column+i))
+			}
+		}
+	}
+	for i, name := range header {
+		cell := area.Sheet.Cell(area.Row-1, area.Column+i-1)
+		if cell.Value != name || cell.Type() != xlsx.CellTypeString {
+			cell.SetString(name)
+		}
+	}
+	if totalsRow != 0 {
+		for i, name := range header {
+			cell := area.Sheet.Cell(totalsRow-1, area.Column+i-1)
+			if f, ok := t.Options.Totals[name]; ok {
+				cell.SetFormula(fmt.Sprintf("SUBTOTAL(%d,%s[%s])", totalsFunctions[f].code, t.Name, escapeColumnName(name)))
+			} else if i == 0 {
+				cell.SetString(t.totalsLabel())
+			}
+		}
+	}
+	t.totalsRow = totalsRow
+	return nil
+}
+
+func (t *Table) totalsLabel() string {
+	if t.Options.TotalsLabel == "" {
+		return "Total"
+	}
+	return t.Options.TotalsLabel
+}
+
+// escapeColumnName escapes special characters of column name in structured references
+func escapeColumnName(name string) string {
+	var builder strings.Builder
+	for _, c := range name {
+		if strings.ContainsRune("[]#'", c) {
+			builder.WriteByte('\'')
+		}
+		builder.WriteRune(c)
+	}
+	return builder.String()
+}
+
+type xlsxTable struct {
+	XMLName        xml.Name           `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main table"`
+	ID             int                `xml:"id,attr"`
+	Name           string             `xml:"name,attr"`
+	DisplayName    string             `xml:"displayName,attr"`
+	Ref            string             `xml:"ref,attr"`
+	TotalsRowCount int                `xml:"totalsRowCount,attr,omitempty"`
+	TotalsRowShown *int               `xml:"totalsRowShown,attr"`
+	AutoFilter     xlsxTableFilter    `xml:"autoFilter"`
+	Columns        xlsxTableColumns   `xml:"tableColumns"`
+	StyleInfo      xlsxTableStyleInfo `xml:"tableStyleInfo"`
+}
+
+type xlsxTableFilter struct {
+	Ref string `xml:"ref,attr"`
+}
+
+type xlsxTableColumns struct {
+	Count   int               `xml:"count,attr"`
+	Columns []xlsxTableColumn `xml:"tableColumn"`
+}
+
+type xlsxTableColumn struct {
+	ID                int    `xml:"id,attr"`
+	Name              string `xml:"name,attr"`
+	TotalsRowLabel    string `xml:"totalsRowLabel,attr,omitempty"`
+	TotalsRowFunction string `xml:"totalsRowFunction,attr,omitempty"`
+}
+
+type xlsxTableStyleInfo struct {
+	Name              string `xml:"name,attr"`
+	ShowFirstColumn   int    `xml:"showFirstColumn,attr"`
+	ShowLastColumn    int    `xml:"showLastColumn,attr"`
+	ShowRowStripes    int    `xml:"showRowStripes,attr"`
+	ShowColumnStripes int    `xml:"showColumnStripes,attr"`
+}
+
+// tableParts writes table parts of the sheet and returns tableParts element
+func (b *packageBuilder) tableParts(sheetPart string, tables []*Table) (string, error) {
+	flag := func(value bool) int {
+		if value {
+			return 1
+		}
+		return 0
+	}
+	var ids []string
+	for _, table := range tables {
+		if err := table.validate(); err != nil {
+			return "", err
+		}
+		header := table.Header()
+		area := table.dataArea()
+		for i := range header {
+			// cells are not written here, so header cells changed after AsTable are reported
+			if cell := area.cellAt(area.Row-1, area.Column+i-1); cellKind(cell) != kindText {
+				return "", fmt.Errorf("Header cell %s of table '%s' should be text", cellName(area.Row, area.Column+i), table.Name)
+			}
+		}
+		b.tableCount++
+		element := xlsxTable{
+			ID:          b.tableCount,
+			Name:        table.Name,
+			DisplayName: table.Name,
+			Ref:         table.Ref(),
+			AutoFilter:  xlsxTableFilter{Ref: area.Format(false)},
+			Columns:     xlsxTableColumns{Count: len(header)},
+			StyleInfo: xlsxTableStyleInfo{
+				Name:              table.Options.Style,
+				ShowFirstColumn:   flag(table.Options.FirstColumn),
+				ShowLastColumn:    flag(table.Options.LastColumn),
+				ShowRowStripes:    flag(table.Options.BandedRows),
+				ShowColumnStripes: flag(table.Options.BandedColumns),
+			},
+		}
+		if element.StyleInfo.Name == "" {
+			element.StyleInfo.Name = "TableStyleMedium2"
+		}
+		if table.totalsRow != 0 {
+			element.TotalsRowCount = 1
+		} else {
+			shown := 0
+			element.TotalsRowShown = &shown
+		}
+		for i, name := range header {
+			column := xlsxTableColumn{ID: i + 1, Name: name}
+			if table.totalsRow != 0 {
+				if f, ok := table.Options.Totals[name]; ok {
+					column.TotalsRowFunction = totalsFunctions[f].name
+				} else if i == 0 {
+					column.TotalsRowLabel = table.totalsLabel()
+				}
+			}
+			element.Columns.Columns = append(element.Columns.Columns, column)
+		}
+		content, err := marshalXML(element)
+		if err != nil {
+			return "", err
+		}
+		partName := b.addPart("xl/tables/table%d.xml", "application/vnd.openxmlformats-officedocument.spreadsheetml.table+xml", content)
+		ids = append(ids, b.addRelationship(sheetPart, "table", partName))
+	}
+	var buffer strings.Builder
+	fmt.Fprintf(&buffer, `<tableParts count="%d">`, len(ids))
+	for _, id := range ids {
+		fmt.Fprintf(&buffer, `<tablePart r:id="%s"/>`, id)
+	}
+	buffer.WriteString("</tableParts>")
+	return buffer.String(), nil
+}
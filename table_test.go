@@ -0,0 +1,167 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestAsTable(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	table, err := New(sheet, "A1:C6").AsTable("Sales", &TableOptions{
+		Style:      "TableStyleLight9",
+		BandedRows: true,
+		TotalsRow:  true,
+		Totals:     map[string]AggregateFunc{"Amount": AggregateSum},
+	})
+	if err != nil {
+		t.Fatalf("AsTable should succeed, but %s", err.Error())
+	}
+	if table.Ref() != "A1:C7" {
+		t.Errorf("table ref should include totals row, but %s", table.Ref())
+	}
+	if formula := sheet.Cell(6, 1).Formula(); formula != "SUBTOTAL(109,Sales[Amount])" {
+		t.Errorf("AsTable should write SUBTOTAL formula into totals row, but %s", formula)
+	}
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	tableXML := parts["xl/tables/table1.xml"]
+	expected := []string{
+		`id="1" name="Sales" displayName="Sales" ref="A1:C7" totalsRowCount="1"`,
+		`<autoFilter ref="A1:C6"></autoFilter>`,
+		`<tableColumn id="1" name="Status" totalsRowLabel="Total"></tableColumn><tableColumn id="2" name="Amount" totalsRowFunction="sum"></tableColumn>`,
+		`<tableStyleInfo name="TableStyleLight9" showFirstColumn="0" showLastColumn="0" showRowStripes="1" showColumnStripes="0">`,
+	}
+	for _, text := range expected {
+		if !strings.Contains(tableXML, text) {
+			t.Errorf("table part should contain %s, but %s", text, tableXML)
+		}
+	}
+	if xml := parts["xl/worksheets/sheet1.xml"]; !strings.Contains(xml, `<tableParts count="1"><tablePart r:id="rId1"/></tableParts>`) {
+		t.Errorf("sheet should refer table part, but %s", xml)
+	}
+	if rels := parts["xl/worksheets/_rels/sheet1.xml.rels"]; !strings.Contains(rels, `Target="../tables/table1.xml"`) {
+		t.Errorf("sheet relationships should have table, but %s", rels)
+	}
+	if types := parts["[Content_Types].xml"]; !strings.Contains(types, `PartName="/xl/tables/table1.xml"`) {
+		t.Errorf("content types should have table part, but %s", types)
+	}
+	var buffer bytes.Buffer
+	if err := Write(file, &buffer); err != nil {
+		t.Fatalf("Write should succeed, but %s", err.Error())
+	}
+	if _, err := xlsx.OpenBinary(buffer.Bytes()); err != nil {
+		t.Errorf("written file should be readable, but %s", err.Error())
+	}
+}
+
+func TestAsTableGrows(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	table, _ := New(sheet, "A:C").AsTable("Sales", nil)
+	sheet.Cell(6, 0).SetString("Open")
+	sheet.Cell(6, 1).SetInt(800)
+	sheet.Cell(6, 2).SetString("frank")
+	if table.Ref() != "A1:C7" {
+		t.Errorf("table without totals row should grow with data, but %s", table.Ref())
+	}
+}
+
+func TestTableResize(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	table, err := New(sheet, "A1:C6").AsTable("Sales", &TableOptions{TotalsRow: true, Totals: map[string]AggregateFunc{"Amount": AggregateMax}})
+	if err != nil {
+		t.Fatalf("AsTable should succeed, but %s", err.Error())
+	}
+	if err := table.Resize(New(sheet, "A1:C7")); err != nil {
+		t.Fatalf("Resize should succeed, but %s", err.Error())
+	}
+	if table.Ref() != "A1:C8" {
+		t.Errorf("table should include new totals row, but %s", table.Ref())
+	}
+	if sheet.Cell(7, 0).Value != "Total" || sheet.Cell(7, 1).Formula() != "SUBTOTAL(104,Sales[Amount])" {
+		t.Errorf("totals row should be moved to row 8, but %s", sheet.Cell(7, 0).Value)
+	}
+	if cellKind(sheet.Cell(6, 0)) != kindBlank || sheet.Cell(6, 1).Formula() != "" {
+		t.Errorf("old totals row should be cleared, but %s", sheet.Cell(6, 0).Value)
+	}
+
+	sheet.Cell(8, 2).SetString("note")
+	if err := table.Resize(New(sheet, "A1:C8")); err == nil {
+		t.Errorf("Resize should reject totals row that is not empty")
+	}
+	if table.Ref() != "A1:C8" || sheet.Cell(7, 1).Formula() != "SUBTOTAL(104,Sales[Amount])" {
+		t.Errorf("failed Resize should keep totals row, but %s", table.Ref())
+	}
+}
+
+func TestAsTableWritesCells(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	if _, err := New(sheet, "A1:C5").AsTable("Sales", &TableOptions{TotalsRow: true}); err == nil {
+		t.Errorf("AsTable should reject totals row that is not empty")
+	}
+	if sheet.Cell(5, 0).Value != "Pending" || len(New(sheet).Tables()) != 0 {
+		t.Errorf("failed AsTable should not change cells and tables")
+	}
+
+	sheet.Cell(0, 2).SetString("")
+	if _, err := New(sheet, "A1:C6").AsTable("Sales", nil); err != nil {
+		t.Fatalf("AsTable should succeed, but %s", err.Error())
+	}
+	if sheet.Cell(0, 2).Value != "Column3" {
+		t.Errorf("blank header should be filled by AsTable, but %s", sheet.Cell(0, 2).Value)
+	}
+	sheet.Cell(0, 2).SetString("")
+	if _, err := MarshallParts(file); err == nil {
+		t.Errorf("MarshallParts should reject blank header cell instead of changing it")
+	}
+	if sheet.Cell(0, 2).Value != "" {
+		t.Errorf("MarshallParts should not change cells, but %s", sheet.Cell(0, 2).Value)
+	}
+}
+
+func TestAsTableError(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	if _, err := New(sheet, "A1:C6").AsTable("A1", nil); err == nil {
+		t.Errorf("AsTable should reject name like cell reference")
+	}
+	if _, err := New(sheet, "A1:C6").AsTable("My Table", nil); err == nil {
+		t.Errorf("AsTable should reject name with space")
+	}
+	sheet.Cell(0, 2).SetString("amount")
+	if _, err := New(sheet, "A1:C6").AsTable("Sales", nil); err == nil {
+		t.Errorf("AsTable should reject duplicated header names")
+	}
+	sheet.Cell(0, 2).SetString("Owner")
+	New(sheet, "A1:B6").AsTable("Sales", nil)
+	if _, err := New(sheet, "B1:C6").AsTable("Other", nil); err == nil {
+		t.Errorf("AsTable should reject overlapped table")
+	}
+	if _, err := New(sheet, "E1:F2").AsTable("sales", nil); err == nil {
+		t.Errorf("AsTable should reject duplicated table name")
+	}
+	if tables := New(sheet, "A1").Tables(); len(tables) != 1 {
+		t.Errorf("Tables should return the table, but %d", len(tables))
+	} else {
+		tables[0].Remove()
+	}
+	if tables := New(sheet).Tables(); len(tables) != 0 {
+		t.Errorf("Remove should remove the table, but %d", len(tables))
+	}
+}
+
+func TestTablesDoesntRegisterFile(t *testing.T) {
+	file := createTableFile()
+	New(file.Sheet["Data"], "A1:C6").Tables()
+	if registered(file) {
+		t.Errorf("Tables should not register the file")
+	}
+}
@@ -0,0 +1,445 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/tealeg/xlsx"
+)
+
+// TemplateOptions controls Range.ExecuteTemplate.
+// nil is acceptable and it means zero value of this struct.
+type TemplateOptions struct {
+	InferTypes bool             // Converts numeric, boolean and date like results into typed cells
+	Funcs      template.FuncMap // Additional functions for templates
+}
+
+var repeatPattern = regexp.MustCompile(`^\s*\{\{-?\s*repeat\s+(.+?)\s*-?\}\}`)
+var endPattern = regexp.MustCompile(`\{\{-?\s*end\s*-?\}\}\s*$`)
+var blockActionPattern = regexp.MustCompile(`\{\{-?\s*(if|range|with|block|define|end)\b`)
+
+// isEndMarker returns true if the text ends with {{end}} that is not a part of {{if}}, {{range}} and so on
+// in the text.
+func isEndMarker(text string) bool {
+	match := endPattern.FindStringIndex(text)
+	if match == nil {
+		return false
+	}
+	depth := 0
+	for _, action := range blockActionPattern.FindAllStringSubmatch(text[:match[0]], -1) {
+		if action[1] == "end" {
+			depth--
+		} else {
+			depth++
+		}
+	}
+	return depth == 0
+}
+
+// ExecuteTemplate expands text/template placeholders like {{.Customer.Name}} in cells of selected range.
+//
+// A cell that starts with {{repeat .Items}} marks the first row of a repeated block, and a cell that
+// ends with {{end}} (that doesn't close {{if}}, {{range}} and so on in the cell) marks the last row (the block is one row if it is omitted before the next repeat marker).
+// Rows of the block are cloned for each element of the slice and placeholders in them are expanded with
+// the element. Use {{root}} to access data in the block. Cells below the block are shifted, and merged cells,
+// formulas in the workbook that refer shifted rows, and tables, conditional formats, charts and print settings
+// of the sheet are adjusted. Nested repeat blocks are not supported.
+//
+// All placeholders are evaluated before the sheet is modified, so the sheet is not changed if it returns error.
+// It returns the range after expansion.
+func (r *Range) ExecuteTemplate(data interface{}, opts *TemplateOptions) (*Range, error) {
+	if opts == nil {
+		opts = &TemplateOptions{}
+	}
+	rowCount, columnCount := r.size()
+	last := r.Row + rowCount - 1
+	var blocks []*templateBlock
+	for row := r.Row; row <= last; {
+		repeatColumn, items, err := r.findRepeat(row, columnCount, data, opts)
+		if err != nil {
+			return nil, err
+		}
+		if repeatColumn == -1 {
+			cells, err := r.expandRow(row, columnCount, -1, -1, data, data, opts)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, &templateBlock{row: row, height: 1, count: 1, rows: [][]templateCell{cells}})
+			row++
+			continue
+		}
+		blockEnd, endColumn := row, -1
+		for candidate := row; candidate <= last; candidate++ {
+			// block without end marker is one row, so it doesn't take end marker of the next block
+			if candidate > row && r.markerColumn(candidate, columnCount, repeatPattern.MatchString) != -1 {
+				break
+			}
+			if column := r.markerColumn(candidate, columnCount, isEndMarker); column != -1 {
+				blockEnd, endColumn = candidate, column
+				break
+			}
+		}
+		block := &templateBlock{row: row, height: blockEnd - row + 1, count: len(items)}
+		for _, item := range items {
+			for offset := 0; offset < block.height; offset++ {
+				markerRow := row + offset
+				first, end := -1, -1
+				if offset == 0 {
+					first = repeatColumn
+				}
+				if markerRow == blockEnd {
+					end = endColumn
+				}
+				cells, err := r.expandRow(markerRow, columnCount, first, end, item, data, opts)
+				if err != nil {
+					return nil, err
+				}
+				block.rows = append(block.rows, cells)
+			}
+		}
+		blocks = append(blocks, block)
+		row = blockEnd + 1
+	}
+	shift := 0
+	for _, block := range blocks {
+		row := block.row + shift
+		if block.count == 0 {
+			deleteRows(r.Sheet, row, block.height)
+			shift -= block.height
+			continue
+		}
+		if extra := (block.count - 1) * block.height; extra > 0 {
+			insertRows(r.Sheet, row+block.height, extra, row)
+			for i := 1; i < block.count; i++ {
+				for offset := 0; offset < block.height; offset++ {
+					copyRow(r.Sheet, row+offset, row+i*block.height+offset)
+				}
+			}
+			shift += extra
+		}
+		for i, cells := range block.rows {
+			for _, expanded := range cells {
+				cell := r.Sheet.Cell(row+i-1, r.Column+expanded.column-1)
+				if opts.InferTypes && expanded.executed {
+					inferCellValue(cell, expanded.text, &ImportOptions{InferTypes: true})
+				} else {
+					cell.SetString(expanded.text)
+				}
+			}
+		}
+	}
+	return New(r.Sheet, r.Row, r.Column, last+shift-r.Row+1, columnCount), nil
+}
+
+// templateBlock is rows of the template and their expanded cells
+type templateBlock struct {
+	row    int              // First row (1 origin) before expansion
+	height int              // Number of rows of the block
+	count  int              // Number of copies of the block. It is zero for empty slice.
+	rows   [][]templateCell // Expanded cells of each row of copies
+}
+
+// templateCell is a cell that is changed by the template
+type templateCell struct {
+	column   int    // Column index relative from left of the range (0 origin)
+	text     string // Text after expansion
+	executed bool   // True if text is a result of placeholders. Otherwise markers are just removed.
+}
+
+// markerColumn returns column index (0 origin) of the first text cell in the row (1 origin) that is
+// a marker, or -1.
+func (r *Range) markerColumn(row, columnCount int, isMarker func(string) bool) int {
+	for column := 0; column < columnCount; column++ {
+		cell := r.cellAt(row-1, r.Column+column-1)
+		if cell == nil || cell.Type() != xlsx.CellTypeString && cell.Type() != xlsx.CellTypeInline {
+			continue
+		}
+		if isMarker(cell.Value) {
+			return column
+		}
+	}
+	return -1
+}
+
+// findRepeat finds repeat marker in the row (1 origin) and evaluates its slice.
+// It returns column index (0 origin) of the marker or -1 if the row doesn't have it.
+func (r *Range) findRepeat(row, columnCount int, data interface{}, opts *TemplateOptions) (int, []interface{}, error) {
+	column := r.markerColumn(row, columnCount, repeatPattern.MatchString)
+	if column == -1 {
+		return -1, nil, nil
+	}
+	text := r.cellAt(row-1, r.Column+column-1).Value
+	match := repeatPattern.FindStringSubmatchIndex(text)
+	address := cellName(row, r.Column+column)
+	var value interface{}
+	funcs := template.FuncMap{
+		"repeat": func(v interface{}) string {
+			value = v
+			return ""
+		},
+	}
+	if _, err := executeText("{{repeat "+text[match[2]:match[3]]+"}}", data, data, funcs, opts); err != nil {
+		return -1, nil, fmt.Errorf("Template error at %s: %s", address, err.Error())
+	}
+	if value == nil {
+		return column, nil, nil
+	}
+	slice := reflect.ValueOf(value)
+	if slice.Kind() != reflect.Slice && slice.Kind() != reflect.Array {
+		return -1, nil, fmt.Errorf("Template error at %s: repeat needs slice, but %T", address, value)
+	}
+	items := make([]interface{}, slice.Len())
+	for i := range items {
+		items[i] = slice.Index(i).Interface()
+	}
+	return column, items, nil
+}
+
+// expandRow expands placeholders in cells of the row (1 origin) without changing cells.
+// Repeat marker in repeatColumn and end marker in endColumn (0 origin, -1 means none) are removed.
+func (r *Range) expandRow(row, columnCount, repeatColumn, endColumn int, data, root interface{}, opts *TemplateOptions) ([]templateCell, error) {
+	var result []templateCell
+	for column := 0; column < columnCount; column++ {
+		cell := r.cellAt(row-1, r.Column+column-1)
+		if cell == nil || cell.Formula() != "" {
+			continue
+		}
+		text := cell.Value
+		stripped := false
+		if column == repeatColumn {
+			text = text[repeatPattern.FindStringIndex(text)[1]:]
+			stripped = true
+		}
+		if column == endColumn {
+			text = text[:endPattern.FindStringIndex(text)[0]]
+			stripped = true
+		}
+		if !strings.Contains(text, "{{") {
+			if stripped {
+				result = append(result, templateCell{column: column, text: text})
+			}
+			continue
+		}
+		expanded, err := executeText(text, data, root, nil, opts)
+		if err != nil {
+			return nil, fmt.Errorf("Template error at %s: %s", cellName(row, r.Column+column), err.Error())
+		}
+		result = append(result, templateCell{column: column, text: expanded, executed: true})
+	}
+	return result, nil
+}
+
+func executeText(text string, data, root interface{}, funcs template.FuncMap, opts *TemplateOptions) (string, error) {
+	tmpl := template.New("cell").Funcs(template.FuncMap{
+		"root": func() interface{} { return root },
+	})
+	if opts.Funcs != nil {
+		tmpl = tmpl.Funcs(opts.Funcs)
+	}
+	if funcs != nil {
+		tmpl = tmpl.Funcs(funcs)
+	}
+	tmpl, err := tmpl.Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// insertRows inserts blank rows before row at (1 origin) like Excel.
+// Merged cells of the sheet, formulas and data validations that refer the sheet in the workbook,
+// and features of the sheet (see moveRowFeatures) are adjusted. See insertRowsInFormula for expandFrom.
+func insertRows(sheet *xlsx.Sheet, at, count, expandFrom int) {
+	mapWorkbookFormulas(sheet, nil, func(formula string, local bool) string {
+		return insertRowsInFormula(formula, sheet.Name, local, at, count, expandFrom)
+	})
+	for index, row := range sheet.Rows {
+		if row == nil {
+			continue
+		}
+		for _, cell := range row.Cells {
+			if cell != nil && index+1 < at && index+1+cell.VMerge >= at {
+				cell.VMerge += count
+			}
+		}
+	}
+	if at-1 < len(sheet.Rows) {
+		inserted := make([]*xlsx.Row, count)
+		for i := range inserted {
+			inserted[i] = &xlsx.Row{Sheet: sheet}
+		}
+		sheet.Rows = append(sheet.Rows[:at-1], append(inserted, sheet.Rows[at-1:]...)...)
+		sheet.MaxRow = max(sheet.MaxRow+count, len(sheet.Rows))
+	}
+	moveRowFeatures(sheet, func(r *Range) *Range {
+		return insertRowsInRange(r, at, count, expandFrom)
+	}, func(row int) int {
+		if row >= at {
+			return row + count
+		}
+		return row
+	}, func(formula string) string {
+		return insertRowsInFormula(formula, sheet.Name, true, at, count, expandFrom)
+	})
+}
+
+// deleteRows deletes rows from row at (1 origin) like Excel.
+// Merged cells of the sheet, formulas and data validations that refer the sheet in the workbook,
+// and features of the sheet (see moveRowFeatures) are adjusted.
+func deleteRows(sheet *xlsx.Sheet, at, count int) {
+	if at-1 < len(sheet.Rows) {
+		end := min(at-1+count, len(sheet.Rows))
+		sheet.Rows = append(sheet.Rows[:at-1], sheet.Rows[end:]...)
+		sheet.MaxRow = max(sheet.MaxRow-(end-at+1), len(sheet.Rows))
+	}
+	mapWorkbookFormulas(sheet, nil, func(formula string, local bool) string {
+		return deleteRowsInFormula(formula, sheet.Name, local, at, count)
+	})
+	for index, row := range sheet.Rows {
+		if row == nil {
+			continue
+		}
+		for _, cell := range row.Cells {
+			if cell == nil {
+				continue
+			}
+			anchor := index + 1
+			if spanEnd := anchor + cell.VMerge; anchor < at && spanEnd >= at {
+				if spanEnd >= at+count {
+					cell.VMerge -= count
+				} else {
+					cell.VMerge = at - 1 - anchor
+				}
+			}
+		}
+	}
+	moveRowFeatures(sheet, func(r *Range) *Range {
+		return deleteRowsInRange(r, at, count)
+	}, func(row int) int {
+		if row >= at+count {
+			return row - count
+		} else if row >= at {
+			return 0
+		}
+		return row
+	}, func(formula string) string {
+		return deleteRowsInFormula(formula, sheet.Name, true, at, count)
+	})
+}
+
+// mapWorkbookFormulas replaces formulas of cells and data validations in all sheets of the workbook of the sheet.
+// local of mapper is true for formulas in the sheet. Formulas of cells in exclude are kept if it is not nil.
+func mapWorkbookFormulas(sheet *xlsx.Sheet, exclude *Range, mapper func(formula string, local bool) string) {
+	sheets := []*xlsx.Sheet{sheet}
+	if sheet.File != nil {
+		sheets = sheet.File.Sheets
+	}
+	// tealeg/xlsx shares a data validation among columns of a loaded file, so each one is mapped once
+	mapped := make(map[interface{}]bool)
+	for _, target := range sheets {
+		local := target == sheet
+		for rowIndex, row := range target.Rows {
+			if row == nil {
+				continue
+			}
+			for columnIndex, cell := range row.Cells {
+				if cell == nil {
+					continue
+				}
+				excluded := exclude != nil && exclude.Sheet == target && exclude.contains(rowIndex+1, columnIndex+1)
+				if formula := cell.Formula(); formula != "" && !excluded {
+					setFormula(cell, cell, mapper(formula, local))
+				}
+				if dataValidation := cell.DataValidation; dataValidation != nil && !mapped[dataValidation] {
+					dataValidation.Formula1 = mapper(dataValidation.Formula1, local)
+					dataValidation.Formula2 = mapper(dataValidation.Formula2, local)
+					mapped[dataValidation] = true
+				}
+			}
+		}
+		for _, col := range target.Cols {
+			if col == nil {
+				continue
+			}
+			for _, dataValidation := range col.DataValidation {
+				if !mapped[dataValidation] {
+					dataValidation.Formula1 = mapper(dataValidation.Formula1, local)
+					dataValidation.Formula2 = mapper(dataValidation.Formula2, local)
+					mapped[dataValidation] = true
+				}
+			}
+		}
+	}
+}
+
+// insertRowsInRange returns the range moved like inserting rows before row at (1 origin).
+// Ranges across at are expanded, and ranges that end at the row just above at are also expanded
+// if the last row is at or below expandFrom (like a table whose last row is repeated). Whole-column
+// ranges are not changed.
+func insertRowsInRange(r *Range, at, count, expandFrom int) *Range {
+	if r.NumRows == AllRows {
+		return r
+	}
+	start, end := r.Row, r.Row+r.NumRows-1
+	if start >= at {
+		start += count
+	}
+	if end >= at || expandFrom != 0 && end == at-1 && end >= expandFrom {
+		end += count
+	}
+	moved := *r
+	moved.Row, moved.NumRows = start, end-start+1
+	return &moved
+}
+
+// deleteRowsInRange returns the range moved like deleting rows from row at (1 origin).
+// Deleted rows are removed from the range, and it returns nil if all rows are deleted.
+func deleteRowsInRange(r *Range, at, count int) *Range {
+	if r.NumRows == AllRows {
+		return r
+	}
+	start, end := r.Row, r.Row+r.NumRows-1
+	if start >= at+count {
+		start -= count
+	} else if start >= at {
+		start = at
+	}
+	if end >= at+count {
+		end -= count
+	} else if end >= at {
+		end = at - 1
+	}
+	if end < start {
+		return nil
+	}
+	moved := *r
+	moved.Row, moved.NumRows = start, end-start+1
+	return &moved
+}
+
+// copyRow copies cells and height of row src to row dst (1 origin). Formulas are shifted.
+func copyRow(sheet *xlsx.Sheet, src, dst int) {
+	from := sheet.Row(src - 1)
+	to := sheet.Row(dst - 1)
+	copied := *from
+	copied.Cells = nil
+	*to = copied
+	for _, cell := range from.Cells {
+		target := to.AddCell()
+		if cell == nil {
+			continue
+		}
+		copyCell(target, cell)
+		target.HMerge = cell.HMerge
+		target.VMerge = cell.VMerge
+		setFormula(target, cell, shiftFormula(cell.Formula(), dst-src, 0))
+	}
+}
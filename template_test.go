@@ -0,0 +1,321 @@
+package xlsxrange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+type invoiceLine struct {
+	Name  string
+	Price int
+}
+
+type invoice struct {
+	Customer struct{ Name string }
+	Lines    []invoiceLine
+}
+
+func createTemplateFile() *xlsx.File {
+	file := xlsx.NewFile()
+	sheet, _ := file.AddSheet("Invoice")
+	sheet.Cell(0, 0).SetString("Dear {{.Customer.Name}}")
+	sheet.Cell(2, 0).SetString("{{repeat .Lines}}{{.Name}}")
+	sheet.Cell(2, 1).SetString("{{.Price}}")
+	sheet.Cell(2, 2).SetString("{{root.Customer.Name}}")
+	sheet.Cell(3, 0).SetString("Total")
+	sheet.Cell(3, 1).SetFormula("SUM(B3:B3)")
+	sheet.Cell(4, 0).SetString("Thanks")
+	sheet.Cell(4, 0).HMerge = 1
+	return file
+}
+
+func TestExecuteTemplate(t *testing.T) {
+	file := createTemplateFile()
+	sheet := file.Sheet["Invoice"]
+	data := invoice{Lines: []invoiceLine{{"Apple", 100}, {"Banana", 200}, {"Cherry", 300}}}
+	data.Customer.Name = "ACME"
+
+	result, err := New(sheet, "A1:C5").ExecuteTemplate(data, &TemplateOptions{InferTypes: true})
+	if err != nil {
+		t.Fatalf("ExecuteTemplate should succeed, but %s", err.Error())
+	}
+	if result.Format(false) != "A1:C7" {
+		t.Errorf("result range should be expanded to A1:C7, but %s", result.Format(false))
+	}
+	if value := sheet.Cell(0, 0).Value; value != "Dear ACME" {
+		t.Errorf("placeholder should be expanded, but %s", value)
+	}
+	var names []string
+	for row := 2; row < 5; row++ {
+		names = append(names, sheet.Cell(row, 0).Value)
+	}
+	if strings.Join(names, ",") != "Apple,Banana,Cherry" {
+		t.Errorf("repeated rows should have element values, but %v", names)
+	}
+	if cell := sheet.Cell(4, 1); cell.Value != "300" || cell.Type() != xlsx.CellTypeNumeric {
+		t.Errorf("price should be inferred as number, but %s", cell.Value)
+	}
+	if value := sheet.Cell(3, 2).Value; value != "ACME" {
+		t.Errorf("root should be accessible in repeated rows, but %s", value)
+	}
+	if formula := sheet.Cell(5, 1).Formula(); formula != "SUM(B3:B5)" {
+		t.Errorf("total formula should be shifted and expanded, but %s", formula)
+	}
+	if cell := sheet.Cell(6, 0); cell.Value != "Thanks" || cell.HMerge != 1 {
+		t.Errorf("merged cell should be shifted, but %s %d", cell.Value, cell.HMerge)
+	}
+}
+
+func TestExecuteTemplateWithEmptySlice(t *testing.T) {
+	file := createTemplateFile()
+	sheet := file.Sheet["Invoice"]
+	result, err := New(sheet, "A1:C5").ExecuteTemplate(invoice{}, nil)
+	if err != nil {
+		t.Fatalf("ExecuteTemplate should succeed, but %s", err.Error())
+	}
+	if result.Format(false) != "A1:C4" {
+		t.Errorf("result range should be shrunk to A1:C4, but %s", result.Format(false))
+	}
+	if value := sheet.Cell(2, 0).Value; value != "Total" {
+		t.Errorf("rows below block should be shifted up, but %s", value)
+	}
+	if formula := sheet.Cell(2, 1).Formula(); formula != "SUM(#REF!)" {
+		t.Errorf("reference to deleted block should be #REF!, but %s", formula)
+	}
+}
+
+func TestExecuteTemplateWithEmptySliceShrinksAreas(t *testing.T) {
+	file := xlsx.NewFile()
+	sheet, _ := file.AddSheet("Lines")
+	other, _ := file.AddSheet("Summary")
+	sheet.Cell(0, 0).SetString("Qty")
+	sheet.Cell(1, 0).SetString("{{repeat .}}{{.Price}}")
+	sheet.Cell(2, 0).SetString("-{{end}}")
+	sheet.Cell(3, 0).SetFormula("SUM(A2:A3)")
+	sheet.Cell(3, 1).SetFormula("SUM(A1:A3)+SUM(A3:A2)")
+	sheet.Cell(3, 2).SetFormula("COUNTA(A2:A4)")
+	other.Cell(0, 0).SetFormula("SUM(Lines!A2:A3,Lines!A1:A4)")
+	if _, err := New(sheet, "A1:A4").ExecuteTemplate([]invoiceLine{}, nil); err != nil {
+		t.Fatalf("ExecuteTemplate should succeed, but %s", err.Error())
+	}
+	expected := map[*xlsx.Cell]string{
+		sheet.Cell(1, 0): "SUM(#REF!)",
+		sheet.Cell(1, 1): "SUM(A1:A1)+SUM(#REF!)",
+		sheet.Cell(1, 2): "COUNTA(A2:A2)",
+		other.Cell(0, 0): "SUM(Lines!#REF!,Lines!A1:A2)",
+	}
+	for cell, formula := range expected {
+		if cell.Formula() != formula {
+			t.Errorf("formula should be %s, but %s", formula, cell.Formula())
+		}
+	}
+}
+
+func TestExecuteTemplateMultiRowBlock(t *testing.T) {
+	file := xlsx.NewFile()
+	sheet, _ := file.AddSheet("Sheet")
+	sheet.Cell(0, 0).SetString("{{repeat .}}{{.Name}}")
+	sheet.Cell(1, 0).SetString("{{.Price}}{{end}}")
+	sheet.Cell(2, 0).SetString("footer")
+	_, err := New(sheet, "A1:A3").ExecuteTemplate([]invoiceLine{{"Apple", 100}, {"Banana", 200}}, nil)
+	if err != nil {
+		t.Fatalf("ExecuteTemplate should succeed, but %s", err.Error())
+	}
+	var values []string
+	for row := 0; row < 5; row++ {
+		values = append(values, sheet.Cell(row, 0).Value)
+	}
+	if strings.Join(values, ",") != "Apple,100,Banana,200,footer" {
+		t.Errorf("block of 2 rows should be repeated, but %v", values)
+	}
+}
+
+func TestExecuteTemplateWithActions(t *testing.T) {
+	file := xlsx.NewFile()
+	sheet, _ := file.AddSheet("Sheet")
+	sheet.Cell(0, 0).SetString("{{repeat .Items}}{{.Name}}")
+	sheet.Cell(0, 1).SetString("{{if .Price}}{{.Price}}{{else}}free{{end}}")
+	sheet.Cell(1, 0).SetString("{{range .Tags}}[{{.}}]{{end}}")
+	sheet.Cell(1, 1).SetString("{{.Price}}{{end}}")
+	sheet.Cell(2, 0).SetString("Total{{if .Discount}} (discounted){{end}}")
+	data := map[string]interface{}{
+		"Items": []map[string]interface{}{
+			{"Name": "Apple", "Price": 100, "Tags": []string{"fruit", "red"}},
+			{"Name": "Water", "Price": 0, "Tags": []string{}},
+		},
+		"Discount": true,
+	}
+	result, err := New(sheet, "A1:B3").ExecuteTemplate(data, nil)
+	if err != nil {
+		t.Fatalf("ExecuteTemplate should succeed, but %s", err.Error())
+	}
+	if result.Format(false) != "A1:B5" {
+		t.Errorf("result range should be expanded to A1:B5, but %s", result.Format(false))
+	}
+	var values []string
+	for row := 0; row < 5; row++ {
+		values = append(values, sheet.Cell(row, 0).Value+"|"+sheet.Cell(row, 1).Value)
+	}
+	if strings.Join(values, ",") != "Apple|100,[fruit][red]|100,Water|free,|0,Total (discounted)|" {
+		t.Errorf("{{end}} of actions should not end the block, but %v", values)
+	}
+}
+
+func TestExecuteTemplateWithActionAfterOneRowBlock(t *testing.T) {
+	file := xlsx.NewFile()
+	sheet, _ := file.AddSheet("Sheet")
+	sheet.Cell(0, 0).SetString("{{repeat .Items}}{{.Name}}")
+	sheet.Cell(0, 1).SetString("{{.Qty}}")
+	sheet.Cell(1, 0).SetString("Total{{if .Discount}} (discounted){{end}}")
+	data := map[string]interface{}{
+		"Items":    []map[string]interface{}{{"Name": "Apple", "Qty": 1}, {"Name": "Banana", "Qty": 2}},
+		"Discount": false,
+	}
+	if _, err := New(sheet, "A1:B2").ExecuteTemplate(data, nil); err != nil {
+		t.Fatalf("ExecuteTemplate should succeed, but %s", err.Error())
+	}
+	var values []string
+	for row := 0; row < 3; row++ {
+		values = append(values, sheet.Cell(row, 0).Value+"|"+sheet.Cell(row, 1).Value)
+	}
+	if strings.Join(values, ",") != "Apple|1,Banana|2,Total|" {
+		t.Errorf("block should be one row, but %v", values)
+	}
+}
+
+func TestExecuteTemplateTwoBlocks(t *testing.T) {
+	file := xlsx.NewFile()
+	sheet, _ := file.AddSheet("Sheet")
+	sheet.Cell(0, 0).SetString("{{repeat .A}}{{.}}")
+	sheet.Cell(1, 0).SetString("middle")
+	sheet.Cell(2, 0).SetString("{{repeat .B}}{{.}}")
+	sheet.Cell(3, 0).SetString("-{{end}}")
+	data := map[string][]string{"A": {"a1", "a2"}, "B": {"b1", "b2"}}
+	result, err := New(sheet, "A1:A4").ExecuteTemplate(data, nil)
+	if err != nil {
+		t.Fatalf("ExecuteTemplate should succeed, but %s", err.Error())
+	}
+	if result.Format(false) != "A1:A7" {
+		t.Errorf("result range should be expanded to A1:A7, but %s", result.Format(false))
+	}
+	var values []string
+	for row := 0; row < 7; row++ {
+		values = append(values, sheet.Cell(row, 0).Value)
+	}
+	if strings.Join(values, ",") != "a1,a2,middle,b1,-,b2,-" {
+		t.Errorf("one-row block should not take end marker of the next block, but %v", values)
+	}
+}
+
+func TestExecuteTemplateErrorKeepsSheet(t *testing.T) {
+	file := createTemplateFile()
+	sheet := file.Sheet["Invoice"]
+	sheet.Cell(4, 1).SetString("{{.Missing.Field}}")
+	data := invoice{Lines: []invoiceLine{{"Apple", 100}, {"Banana", 200}}}
+	if _, err := New(sheet, "A1:C5").ExecuteTemplate(data, nil); err == nil || !strings.Contains(err.Error(), "B5") {
+		t.Fatalf("ExecuteTemplate should return error at B5, but %v", err)
+	}
+	if value := sheet.Cell(2, 0).Value; value != "{{repeat .Lines}}{{.Name}}" {
+		t.Errorf("repeat marker should be kept on error, but %s", value)
+	}
+	if value := sheet.Cell(0, 0).Value; value != "Dear {{.Customer.Name}}" || len(sheet.Rows) != 5 {
+		t.Errorf("sheet should not be changed on error, but %s and %d rows", value, len(sheet.Rows))
+	}
+}
+
+func TestExecuteTemplateAdjustsWorkbook(t *testing.T) {
+	file := createTemplateFile()
+	sheet := file.Sheet["Invoice"]
+	rates, _ := file.AddSheet("Rates")
+	rates.Cell(8, 1).SetInt(3)
+	summary, _ := file.AddSheet("Summary")
+	sheet.Cell(3, 2).SetFormula("Rates!A5*2+'Rates'!B9+'Q1 Sales'!A5")
+	summary.Cell(0, 0).SetFormula("SUM(Invoice!B3:B3)+'Invoice'!A5+A5")
+	summary.Cell(1, 0).SetDataValidation(xlsx.NewXlsxCellDataValidation(true))
+	summary.Cell(1, 0).DataValidation.Type = "list"
+	summary.Cell(1, 0).DataValidation.Formula1 = "Invoice!$A$4:$A$5"
+	format, _ := New(sheet, "A4:C4").AddConditionalFormat(ConditionalRule{Type: RuleExpression, Formula1: "$B$4>0", Format: &ConditionalStyle{Bold: true}})
+	table, err := New(sheet, "A2:C3").AsTable("Lines", nil)
+	if err != nil {
+		t.Fatalf("AsTable should succeed, but %s", err.Error())
+	}
+	New(sheet, "A1:C5").SetPrintArea()
+	New(sheet, "A4:C4").AddPageBreaks()
+	New(sheet, "B3").AddValidation(&Validation{Type: ValidateWhole, Formula1: "0", Formula2: "1000"})
+	New(sheet, "C4").AddValidation(&Validation{Type: ValidateList, List: []string{"Yes", "No"}})
+	chart := NewChart(ChartColumn).Series("Price", New(sheet, "A3:A3"), New(sheet, "B3:B3"))
+	chart.PlaceAt(New(summary, "C2:H10"))
+
+	data := invoice{Lines: []invoiceLine{{"Apple", 100}, {"Banana", 200}, {"Cherry", 300}}}
+	if _, err := New(sheet, "A1:C5").ExecuteTemplate(data, nil); err != nil {
+		t.Fatalf("ExecuteTemplate should succeed, but %s", err.Error())
+	}
+	if formula := sheet.Cell(5, 2).Formula(); formula != "Rates!A5*2+'Rates'!B9+'Q1 Sales'!A5" {
+		t.Errorf("references to other sheets should be kept, but %s", formula)
+	}
+	if formula := summary.Cell(0, 0).Formula(); formula != "SUM(Invoice!B3:B5)+'Invoice'!A7+A5" {
+		t.Errorf("references from other sheets should be moved, but %s", formula)
+	}
+	if formula := summary.Cell(1, 0).DataValidation.Formula1; formula != "Invoice!$A$6:$A$7" {
+		t.Errorf("validation list should be moved, but %s", formula)
+	}
+	if validations := New(sheet, "A1:C8").Validations(); len(validations) != 2 ||
+		validations[0].Range.Format(false) != "B3:B5" || validations[1].Range.Format(false) != "C6" {
+		t.Errorf("validations should be expanded and moved, but %v", validations)
+	}
+	if format.Range.Format(false) != "A6:C6" || format.Rules[0].Formula1 != "$B$6>0" {
+		t.Errorf("conditional format should be moved, but %s %s", format.Range.Format(false), format.Rules[0].Formula1)
+	}
+	if table.Ref() != "A2:C5" {
+		t.Errorf("table should be expanded to repeated rows, but %s", table.Ref())
+	}
+	if area := New(sheet).PrintArea(); len(area) != 1 || area[0].Format(false) != "A1:C7" {
+		t.Errorf("print area should be expanded, but %v", area)
+	}
+	if rowBreaks, _ := New(sheet).PageBreaks(); len(rowBreaks) != 2 || rowBreaks[0] != 5 || rowBreaks[1] != 6 {
+		t.Errorf("page breaks should be moved, but %v", rowBreaks)
+	}
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	if chartXML := parts["xl/charts/chart1.xml"]; !strings.Contains(chartXML, "Invoice!$B$3:$B$5") {
+		t.Errorf("chart series should be expanded, but %s", chartXML)
+	}
+}
+
+func TestExecuteTemplateDeleteAdjustsWorkbook(t *testing.T) {
+	file := createTemplateFile()
+	sheet := file.Sheet["Invoice"]
+	summary, _ := file.AddSheet("Summary")
+	summary.Cell(0, 0).SetFormula("Invoice!B4+Invoice!A3")
+	New(sheet, "A3:C5").SetAutoFilter()
+	New(sheet, "5:5").SetPrintTitles()
+	if _, err := New(sheet, "A1:C5").ExecuteTemplate(invoice{}, nil); err != nil {
+		t.Fatalf("ExecuteTemplate should succeed, but %s", err.Error())
+	}
+	if formula := summary.Cell(0, 0).Formula(); formula != "Invoice!B3+Invoice!#REF!" {
+		t.Errorf("references from other sheets should be moved, but %s", formula)
+	}
+	if filter := New(sheet).AutoFilter(); filter == nil || filter.Format(false) != "A3:C4" {
+		t.Errorf("autofilter should be shrunk, but %v", filter)
+	}
+	if rows, _ := New(sheet).PrintTitles(); rows == nil || referenceFormula(rows) != "Invoice!$4:$4" {
+		t.Errorf("print title rows should be moved, but %v", rows)
+	}
+}
+
+func TestExecuteTemplateError(t *testing.T) {
+	file := xlsx.NewFile()
+	sheet, _ := file.AddSheet("Sheet")
+	sheet.Cell(1, 1).SetString("{{.Name")
+	if _, err := New(sheet, "A1:B2").ExecuteTemplate(nil, nil); err == nil || !strings.Contains(err.Error(), "B2") {
+		t.Errorf("ExecuteTemplate should return error with address, but %v", err)
+	}
+	sheet.Cell(1, 1).SetString("{{repeat .Name}}")
+	if _, err := New(sheet, "A1:B2").ExecuteTemplate(invoiceLine{Name: "x"}, nil); err == nil {
+		t.Errorf("ExecuteTemplate should return error when repeat target is not slice")
+	}
+}
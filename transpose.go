@@ -0,0 +1,65 @@
+package xlsxrange
+
+import (
+	"github.com/tealeg/xlsx"
+)
+
+// Transpose returns cells in selected range with rows and columns swapped.
+//
+// AllRows and AllColumns selections are clamped to the used area first.
+// Missing cells are returned as empty cells that don't belong to the sheet.
+func (r *Range) Transpose() [][]*xlsx.Cell {
+	area := r.clamp()
+	rowCount, columnCount := area.size()
+	if rowCount == 0 || columnCount == 0 {
+		return [][]*xlsx.Cell{}
+	}
+	result := make([][]*xlsx.Cell, columnCount)
+	for column := range result {
+		result[column] = make([]*xlsx.Cell, rowCount)
+		for row := range result[column] {
+			cell := area.cellAt(area.Row+row-1, area.Column+column-1)
+			if cell == nil {
+				// rows can be shorter than the range
+				cell = &xlsx.Cell{}
+			}
+			result[column][row] = cell
+		}
+	}
+	return result
+}
+
+// TransposeTo writes selected cells (values, formulas and styles) to left top corner of dest
+// with rows and columns swapped. It returns the range that was written.
+//
+// AllRows and AllColumns selections are clamped to the used area first.
+func (r *Range) TransposeTo(dest *Range) *Range {
+	return r.clamp().CopyTo(dest, &CopyOptions{Transpose: true})
+}
+
+// clamp returns a range whose AllRows and AllColumns are replaced by the used area.
+// Trailing blank rows and columns are excluded from the used area.
+func (r *Range) clamp() *Range {
+	rowCount, columnCount := r.size()
+	usedRows := rowCount
+	usedColumns := columnCount
+	if r.NumRows == AllRows || r.NumColumns == AllColumns {
+		usedRows = 0
+		usedColumns = 0
+		for row := 0; row < rowCount; row++ {
+			for column := 0; column < columnCount; column++ {
+				if cellKind(r.cellAt(r.Row+row-1, r.Column+column-1)) != kindBlank {
+					usedRows = max(usedRows, row+1)
+					usedColumns = max(usedColumns, column+1)
+				}
+			}
+		}
+		if r.NumRows != AllRows {
+			usedRows = rowCount
+		}
+		if r.NumColumns != AllColumns {
+			usedColumns = columnCount
+		}
+	}
+	return New(r.Sheet, r.Row, r.Column, usedRows, usedColumns)
+}
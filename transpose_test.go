@@ -0,0 +1,46 @@
+package xlsxrange
+
+import "testing"
+
+func TestTranspose(t *testing.T) {
+	file := createFile()
+	cells := New(file.Sheet["Sheet 1"], "B2:D3").Transpose()
+
+	if len(cells) != 3 || len(cells[0]) != 2 {
+		t.Errorf("transposed size should be 3x2, but %dx%d", len(cells), len(cells[0]))
+	}
+	if cells[2][1].Value != "D3" {
+		t.Errorf("cells[2][1] should be 'D3', but %s", cells[2][1].Value)
+	}
+}
+
+func TestTransposeRaggedRows(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	sheet.Rows[2].Cells = sheet.Rows[2].Cells[:1]
+	cells := New(sheet, "A2:C3").Transpose()
+	if len(cells) != 3 || len(cells[2]) != 2 {
+		t.Fatalf("transposed size should be 3x2, but %dx%d", len(cells), len(cells[2]))
+	}
+	if cells[2][0].Value != "C2" || cells[2][1].Value != "" {
+		t.Errorf("missing cell should be empty, but '%s' '%s'", cells[2][0].Value, cells[2][1].Value)
+	}
+}
+
+func TestTransposeTo(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	for _, row := range sheet.Rows[3:] {
+		for _, cell := range row.Cells {
+			cell.SetString("")
+		}
+	}
+
+	written := New(sheet, "A:B").TransposeTo(New(file.Sheet["Sheet 2"], "A1"))
+	if written.Format(false) != "A1:C2" {
+		t.Errorf("written range should be 'A1:C2', but %s", written.Format(false))
+	}
+	if written.GetCellAt(1, 2).Value != "B3" {
+		t.Errorf("C2 should be 'B3', but %s", written.GetCellAt(1, 2).Value)
+	}
+}
@@ -0,0 +1,560 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tealeg/xlsx"
+)
+
+// ValidationType is a kind of data validation
+type ValidationType int
+
+const (
+	ValidateList       ValidationType = iota // Value should be in List or cells of ListRange
+	ValidateWhole                            // Integer compared with Formula1 (and Formula2) by Operator
+	ValidateDecimal                          // Number compared with Formula1 (and Formula2) by Operator
+	ValidateDate                             // Date compared with Formula1 (and Formula2) by Operator
+	ValidateTime                             // Time compared with Formula1 (and Formula2) by Operator
+	ValidateTextLength                       // Length of text compared with Formula1 (and Formula2) by Operator
+	ValidateCustom                           // Formula1 should be true. References are relative to the left top cell.
+)
+
+var validationTypeNames = []string{"list", "whole", "decimal", "date", "time", "textLength", "custom"}
+
+// ValidationErrorStyle is a style of error alert
+type ValidationErrorStyle int
+
+const (
+	ErrorStyleStop        ValidationErrorStyle = iota // Rejects invalid values
+	ErrorStyleWarning                                 // Asks whether to accept invalid values
+	ErrorStyleInformation                             // Only informs invalid values
+)
+
+var validationErrorStyleNames = []string{"stop", "warning", "information"}
+
+// Validation is a data validation rule.
+//
+//	aRange.AddValidation(&xlsxrange.Validation{
+//		Type:     xlsxrange.ValidateWhole,
+//		Operator: xlsxrange.OperatorBetween,
+//		Formula1: "1",
+//		Formula2: "100",
+//		Prompt:   "Input quantity (1-100)",
+//	})
+type Validation struct {
+	Type       ValidationType
+	Operator   ConditionalOperator // Default is OperatorBetween. It is not used by list and custom.
+	Formula1   string              // Formula without "=". Use DateFormula for dates.
+	Formula2   string              // Upper bound of OperatorBetween and OperatorNotBetween
+	List       []string            // Choices of ValidateList
+	ListRange  *Range              // Cells that have choices of ValidateList. It can be in another sheet.
+	AllowBlank bool
+
+	PromptTitle  string // Input prompt shown when the cell is selected
+	Prompt       string
+	ErrorStyle   ValidationErrorStyle
+	ErrorTitle   string // Error alert shown when invalid value is input
+	ErrorMessage string
+}
+
+// RangeValidation is a data validation rule applied to the range
+type RangeValidation struct {
+	Range      *Range
+	Validation *Validation
+}
+
+// DateFormula returns formula of the date for Formula1 and Formula2 of ValidateDate.
+func DateFormula(t time.Time) string {
+	return fmt.Sprintf("DATE(%d,%d,%d)", t.Year(), int(t.Month()), t.Day())
+}
+
+// AddValidation sets data validation to all cells in selected range.
+// Existing validation of the cells is replaced.
+//
+// The validation is stored as column settings that cover the rows of the range, so cells are not created
+// for it, and xlsxrange.Save and xlsxrange.Write write it as one element for the whole range.
+func (r *Range) AddValidation(validation *Validation) error {
+	rule, err := validation.toRule()
+	if err != nil {
+		return fmt.Errorf("Invalid validation for %s: %s", r.Format(true), err.Error())
+	}
+	start, end, columnCount := r.validationArea()
+	r.clearCellValidations(start, end, columnCount)
+	for column := r.Column; column < r.Column+columnCount; column++ {
+		setColumnValidation(r.Sheet, column, start, end, rule)
+	}
+	return nil
+}
+
+// RemoveValidations removes data validation of all cells in selected range.
+// Column settings are cut out of the range.
+func (r *Range) RemoveValidations() {
+	start, end, columnCount := r.validationArea()
+	r.clearCellValidations(start, end, columnCount)
+	for column := r.Column; column < r.Column+columnCount; column++ {
+		setColumnValidation(r.Sheet, column, start, end, nil)
+	}
+}
+
+// validationArea returns the first and last rows (1 origin) and number of columns for validations.
+// Whole rows selection (like "B:B") is extended to the last row of Excel.
+func (r *Range) validationArea() (int, int, int) {
+	rowCount, columnCount := r.size()
+	end := r.Row + rowCount - 1
+	if r.NumRows == AllRows {
+		end = xlsx.Excel2006MaxRowIndex + 1
+	}
+	return r.Row, end, columnCount
+}
+
+// clearCellValidations removes data validations of existing cells in the rows (1 origin) of selected columns
+func (r *Range) clearCellValidations(start, end, columnCount int) {
+	for row := start; row <= end && row-1 < len(r.Sheet.Rows); row++ {
+		for column := r.Column; column < r.Column+columnCount; column++ {
+			if cell := r.cellAt(row-1, column-1); cell != nil {
+				cell.DataValidation = nil
+			}
+		}
+	}
+}
+
+// setColumnValidation sets data validation of the rule to rows from start to end (1 origin) of the column.
+// Existing validations of the column are cut out of the rows. Validations are just removed if rule is nil.
+// Sqref of column validations is kept in sync with their rows, because validationRuleAt uses it.
+func setColumnValidation(sheet *xlsx.Sheet, column, start, end int, rule *validationRule) {
+	col := findColumn(sheet, column)
+	if rule != nil {
+		col = sheetColumn(sheet, column)
+	} else if col == nil {
+		return
+	}
+	existing := col.DataValidation
+	col.DataValidation = nil
+	for _, item := range existing {
+		itemStart, itemEnd := columnValidationRows(item.Sqref, column)
+		if itemStart == 0 || itemEnd < start || itemStart > end {
+			col.DataValidation = append(col.DataValidation, item)
+			continue
+		}
+		for _, rows := range [][2]int{{itemStart, start - 1}, {end + 1, itemEnd}} {
+			if rows[0] > rows[1] {
+				continue
+			}
+			// tealeg/xlsx shares a validation among columns of a loaded file, so it is copied
+			part := *item
+			part.Sqref = columnSqref(column, rows[0], rows[1])
+			col.SetDataValidation(&part, rows[0]-1, rows[1]-1)
+		}
+	}
+	if rule == nil {
+		return
+	}
+	dataValidation := xlsx.NewXlsxCellDataValidation(rule.AllowBlank)
+	dataValidation.ShowInputMessage = rule.ShowInputMessage
+	dataValidation.ShowErrorMessage = rule.ShowErrorMessage
+	dataValidation.ErrorStyle = rule.ErrorStyle
+	dataValidation.ErrorTitle = rule.ErrorTitle
+	dataValidation.Operator = rule.Operator
+	dataValidation.Error = rule.Error
+	dataValidation.PromptTitle = rule.PromptTitle
+	dataValidation.Prompt = rule.Prompt
+	dataValidation.Type = rule.Type
+	dataValidation.Formula1 = rule.Formula1
+	dataValidation.Formula2 = rule.Formula2
+	dataValidation.Sqref = columnSqref(column, start, end)
+	col.SetDataValidation(dataValidation, start-1, end-1)
+}
+
+// moveColumnValidations moves rows of column validations of the sheet. moveRange returns moved range
+// or nil if the range is deleted. Validations that reach the last row of Excel keep reaching it.
+func moveColumnValidations(sheet *xlsx.Sheet, moveRange func(*Range) *Range) {
+	last := xlsx.Excel2006MaxRowIndex + 1
+	for index, col := range sheet.Cols {
+		if col == nil || len(col.DataValidation) == 0 {
+			continue
+		}
+		column := index + 1
+		existing := col.DataValidation
+		col.DataValidation = nil
+		for _, item := range existing {
+			start, end := columnValidationRows(item.Sqref, column)
+			if start == 0 {
+				col.DataValidation = append(col.DataValidation, item)
+				continue
+			}
+			moved := moveRange(New(sheet, start, column, end-start+1, 1))
+			if moved == nil {
+				continue
+			}
+			movedStart, movedEnd := moved.Row, min(moved.Row+moved.NumRows-1, last)
+			if end == last {
+				movedEnd = last
+			}
+			part := *item
+			part.Sqref = columnSqref(column, movedStart, movedEnd)
+			col.SetDataValidation(&part, movedStart-1, movedEnd-1)
+		}
+	}
+}
+
+// columnValidationRows returns the first and last rows (1 origin) of sqref in the column.
+// It returns zeros if sqref doesn't cover the column.
+func columnValidationRows(sqref string, column int) (int, int) {
+	for _, ref := range strings.Fields(sqref) {
+		_, position, err := ParseA1Notation(ref)
+		if err != nil || position[1] > column || position[1]+position[3]-1 < column {
+			continue
+		}
+		return position[0], position[0] + position[2] - 1
+	}
+	return 0, 0
+}
+
+// columnSqref returns sqref of rows from start to end (1 origin) of the column
+func columnSqref(column, start, end int) string {
+	if start == end {
+		return cellName(start, column)
+	}
+	return cellName(start, column) + ":" + cellName(end, column)
+}
+
+var sqrefAttributePattern = regexp.MustCompile(` sqref="([^"]*)"`)
+
+// mergeDataValidations merges dataValidation elements of the worksheet part that have the same rule
+// into one element, because tealeg/xlsx writes an element for each column and cell.
+func mergeDataValidations(worksheet string) (string, error) {
+	if !strings.Contains(worksheet, "<dataValidations") {
+		return worksheet, nil
+	}
+	head, children, tail, err := splitChildren(worksheet)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	buffer.WriteString(head)
+	for _, child := range children {
+		if child.name != "dataValidations" {
+			buffer.WriteString(child.raw)
+			continue
+		}
+		_, validations, _, err := splitChildren(child.raw)
+		if err != nil {
+			return "", err
+		}
+		var rules []string
+		refs := make(map[string][]string)
+		for _, validation := range validations {
+			match := sqrefAttributePattern.FindStringSubmatch(validation.raw)
+			if match == nil {
+				rules = append(rules, validation.raw)
+				continue
+			}
+			rule := sqrefAttributePattern.ReplaceAllString(validation.raw, "")
+			if _, ok := refs[rule]; !ok {
+				rules = append(rules, rule)
+			}
+			refs[rule] = append(refs[rule], strings.Fields(match[1])...)
+		}
+		fmt.Fprintf(&buffer, `<dataValidations count="%d">`, len(rules))
+		for _, rule := range rules {
+			if _, ok := refs[rule]; !ok {
+				buffer.WriteString(rule)
+				continue
+			}
+			index := strings.Index(rule, ">")
+			if rule[index-1] == '/' {
+				index--
+			}
+			fmt.Fprintf(&buffer, `%s sqref="%s"%s`, rule[:index], compressSqref(refs[rule]), rule[index:])
+		}
+		buffer.WriteString(`</dataValidations>`)
+	}
+	buffer.WriteString(tail)
+	return buffer.String(), nil
+}
+
+// compressSqref joins references into the fewest rectangles by merging rows of each column first
+// and then adjacent columns that have the same rows. References that can't be parsed are kept.
+func compressSqref(refs []string) string {
+	var result []string
+	rowsOfColumns := make(map[int][][2]int)
+	for _, ref := range refs {
+		_, position, err := ParseA1Notation(ref)
+		if err != nil || position[2] < 1 || position[3] < 1 {
+			result = append(result, ref)
+			continue
+		}
+		for column := position[1]; column < position[1]+position[3]; column++ {
+			rowsOfColumns[column] = append(rowsOfColumns[column], [2]int{position[0], position[0] + position[2] - 1})
+		}
+	}
+	columnsOfRows := make(map[[2]int][]int)
+	for column, rows := range rowsOfColumns {
+		sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+		merged := [][2]int{rows[0]}
+		for _, span := range rows[1:] {
+			if last := &merged[len(merged)-1]; span[0] <= last[1]+1 {
+				last[1] = max(last[1], span[1])
+			} else {
+				merged = append(merged, span)
+			}
+		}
+		for _, span := range merged {
+			columnsOfRows[span] = append(columnsOfRows[span], column)
+		}
+	}
+	var areas [][4]int // first row, first column, last row, last column
+	for span, columns := range columnsOfRows {
+		sort.Ints(columns)
+		first := columns[0]
+		for i, column := range columns {
+			if i+1 == len(columns) || columns[i+1] != column+1 {
+				areas = append(areas, [4]int{span[0], first, span[1], column})
+				if i+1 < len(columns) {
+					first = columns[i+1]
+				}
+			}
+		}
+	}
+	sort.Slice(areas, func(i, j int) bool {
+		if areas[i][0] != areas[j][0] {
+			return areas[i][0] < areas[j][0]
+		}
+		return areas[i][1] < areas[j][1]
+	})
+	for _, area := range areas {
+		if area[0] == area[2] && area[1] == area[3] {
+			result = append(result, cellName(area[0], area[1]))
+		} else {
+			result = append(result, cellName(area[0], area[1])+":"+cellName(area[2], area[3]))
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+// Validations returns data validations of cells in selected range.
+// Cells that have the same rule are grouped into vertical ranges.
+func (r *Range) Validations() []RangeValidation {
+	rowCount, columnCount := r.size()
+	var result []RangeValidation
+	for column := 0; column < columnCount; column++ {
+		var current *RangeValidation
+		currentKey := ""
+		for row := 0; row < rowCount; row++ {
+			absRow := r.Row + row
+			absColumn := r.Column + column
+			rule := r.validationRuleAt(absRow, absColumn)
+			if rule == nil {
+				current = nil
+				continue
+			}
+			key := rule.key()
+			if current != nil && key == currentKey {
+				current.Range.NumRows++
+				continue
+			}
+			result = append(result, RangeValidation{
+				Range:      New(r.Sheet, absRow, absColumn, 1, 1),
+				Validation: r.fromRule(rule),
+			})
+			current = &result[len(result)-1]
+			currentKey = key
+		}
+	}
+	return result
+}
+
+// ValidationAt returns data validation of the cell (relative position, 0 origin).
+// It returns nil if the cell doesn't have validation.
+func (r *Range) ValidationAt(relRow, relCol int) *Validation {
+	rule := r.validationRuleAt(r.Row+relRow, r.Column+relCol)
+	if rule == nil {
+		return nil
+	}
+	return r.fromRule(rule)
+}
+
+// validationRule has same fields as data validation of tealeg/xlsx.
+// The type of tealeg/xlsx is not exported, so it can't be used as a type of variables.
+type validationRule struct {
+	AllowBlank       bool
+	ShowInputMessage bool
+	ShowErrorMessage bool
+	ErrorStyle       *string
+	ErrorTitle       *string
+	Operator         string
+	Error            *string
+	PromptTitle      *string
+	Prompt           *string
+	Type             string
+	Sqref            string
+	Formula1         string
+	Formula2         string
+}
+
+// validationRuleAt returns data validation of the cell (1 origin) or column setting that covers the cell.
+func (r *Range) validationRuleAt(row, column int) *validationRule {
+	cell := r.cellAt(row-1, column-1)
+	if cell == nil {
+		cell = &xlsx.Cell{}
+	}
+	dataValidation := cell.DataValidation
+	if col := findColumn(r.Sheet, column); dataValidation == nil && col != nil {
+	search:
+		for _, columnValidation := range col.DataValidation {
+			for _, ref := range strings.Fields(columnValidation.Sqref) {
+				_, position, err := ParseA1Notation(ref)
+				if err != nil {
+					continue
+				}
+				if New(r.Sheet, position[0], position[1], position[2], position[3]).contains(row, column) {
+					dataValidation = columnValidation
+					break search
+				}
+			}
+		}
+	}
+	if dataValidation == nil {
+		return nil
+	}
+	return &validationRule{
+		AllowBlank:       dataValidation.AllowBlank,
+		ShowInputMessage: dataValidation.ShowInputMessage,
+		ShowErrorMessage: dataValidation.ShowErrorMessage,
+		ErrorStyle:       dataValidation.ErrorStyle,
+		ErrorTitle:       dataValidation.ErrorTitle,
+		Operator:         dataValidation.Operator,
+		Error:            dataValidation.Error,
+		PromptTitle:      dataValidation.PromptTitle,
+		Prompt:           dataValidation.Prompt,
+		Type:             dataValidation.Type,
+		Sqref:            dataValidation.Sqref,
+		Formula1:         dataValidation.Formula1,
+		Formula2:         dataValidation.Formula2,
+	}
+}
+
+// key returns text that is equal if rules are same except sqref
+func (rule *validationRule) key() string {
+	text := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+	return fmt.Sprintf("%v|%v|%v|%s|%s|%s|%s|%s|%s|%s|%s|%s", rule.AllowBlank, rule.ShowInputMessage, rule.ShowErrorMessage,
+		text(rule.ErrorStyle), text(rule.ErrorTitle), rule.Operator, text(rule.Error), text(rule.PromptTitle),
+		text(rule.Prompt), rule.Type, rule.Formula1, rule.Formula2)
+}
+
+func (v *Validation) toRule() (*validationRule, error) {
+	if v.Type < ValidateList || v.Type > ValidateCustom {
+		return nil, fmt.Errorf("Unknown validation type %d", v.Type)
+	}
+	result := &validationRule{AllowBlank: v.AllowBlank, Type: validationTypeNames[v.Type]}
+	switch v.Type {
+	case ValidateList:
+		if v.ListRange != nil {
+			result.Formula1 = referenceFormula(v.ListRange)
+		} else if len(v.List) > 0 {
+			for _, item := range v.List {
+				if strings.Contains(item, ",") {
+					return nil, fmt.Errorf("List item '%s' should not contain comma", item)
+				}
+			}
+			result.Formula1 = `"` + strings.Join(v.List, ",") + `"`
+			if len(result.Formula1) > 257 {
+				return nil, fmt.Errorf("List should be 255 characters or less")
+			}
+		} else if v.Formula1 != "" {
+			result.Formula1 = v.Formula1
+		} else {
+			return nil, fmt.Errorf("List validation needs List, ListRange or Formula1")
+		}
+	case ValidateCustom:
+		if v.Formula1 == "" {
+			return nil, fmt.Errorf("Custom validation needs Formula1")
+		}
+		result.Formula1 = v.Formula1
+	default:
+		operator := v.Operator
+		if operator == "" {
+			operator = OperatorBetween
+		}
+		if v.Formula1 == "" {
+			return nil, fmt.Errorf("%s validation needs Formula1", result.Type)
+		}
+		if (operator == OperatorBetween || operator == OperatorNotBetween) && v.Formula2 == "" {
+			return nil, fmt.Errorf("%s validation needs Formula2 for %s", result.Type, operator)
+		}
+		result.Operator = string(operator)
+		result.Formula1 = v.Formula1
+		result.Formula2 = v.Formula2
+	}
+	optional := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+	result.ShowInputMessage = v.PromptTitle != "" || v.Prompt != ""
+	result.PromptTitle = optional(v.PromptTitle)
+	result.Prompt = optional(v.Prompt)
+	result.ShowErrorMessage = true
+	result.ErrorStyle = optional(validationErrorStyleNames[v.ErrorStyle])
+	result.ErrorTitle = optional(v.ErrorTitle)
+	result.Error = optional(v.ErrorMessage)
+	return result, nil
+}
+
+func (r *Range) fromRule(rule *validationRule) *Validation {
+	text := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+	result := &Validation{
+		Operator:     ConditionalOperator(rule.Operator),
+		AllowBlank:   rule.AllowBlank,
+		PromptTitle:  text(rule.PromptTitle),
+		Prompt:       text(rule.Prompt),
+		ErrorTitle:   text(rule.ErrorTitle),
+		ErrorMessage: text(rule.Error),
+	}
+	for i, name := range validationTypeNames {
+		if name == rule.Type {
+			result.Type = ValidationType(i)
+		}
+	}
+	for i, name := range validationErrorStyleNames {
+		if name == text(rule.ErrorStyle) {
+			result.ErrorStyle = ValidationErrorStyle(i)
+		}
+	}
+	formula1 := rule.Formula1
+	if result.Type == ValidateList {
+		if strings.HasPrefix(formula1, `"`) && strings.HasSuffix(formula1, `"`) && len(formula1) > 1 {
+			result.List = strings.Split(formula1[1:len(formula1)-1], ",")
+			return result
+		}
+		if sheetName, position, err := ParseA1Notation(formula1); err == nil {
+			sheet := r.Sheet
+			if sheetName != "" && r.File != nil {
+				sheet = r.File.Sheet[sheetName]
+			}
+			if sheet != nil {
+				result.ListRange = New(sheet, position[0], position[1], position[2], position[3])
+				return result
+			}
+		}
+	}
+	result.Formula1 = formula1
+	result.Formula2 = rule.Formula2
+	return result
+}
@@ -0,0 +1,158 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestAddValidation(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 1"]
+	err := New(sheet, "B2:B4").AddValidation(&Validation{
+		Type:         ValidateList,
+		List:         []string{"Open", "Closed"},
+		Prompt:       "Select status",
+		ErrorStyle:   ErrorStyleWarning,
+		ErrorMessage: "Unknown status",
+	})
+	if err != nil {
+		t.Fatalf("AddValidation should succeed, but %s", err.Error())
+	}
+	New(sheet, "C2:C3").AddValidation(&Validation{Type: ValidateList, ListRange: New(file.Sheet["Sheet 2"], "A1:A5")})
+	New(sheet, "D2").AddValidation(&Validation{Type: ValidateWhole, Formula1: "1", Formula2: "100"})
+	New(sheet, "E2").AddValidation(&Validation{
+		Type:     ValidateDate,
+		Operator: OperatorGreaterThanOrEqual,
+		Formula1: DateFormula(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)),
+	})
+
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	xml := parts["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(xml, `sqref="B2:B4"><formula1>&#34;Open,Closed&#34;</formula1>`) {
+		t.Errorf("sheet should have list validation, but %s", xml)
+	}
+	if !strings.Contains(xml, `<formula1>&#39;Sheet 2&#39;!$A$1:$A$5</formula1>`) {
+		t.Errorf("list range should be quoted absolute reference, but %s", xml)
+	}
+	if !strings.Contains(xml, `operator="between" type="whole" sqref="D2"><formula1>1</formula1><formula2>100</formula2>`) {
+		t.Errorf("sheet should have whole number validation, but %s", xml)
+	}
+	if !strings.Contains(xml, `<formula1>DATE(2024,4,1)</formula1>`) {
+		t.Errorf("sheet should have date validation, but %s", xml)
+	}
+}
+
+func TestAddValidationWritesOneElement(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 1"]
+	list := &Validation{Type: ValidateList, List: []string{"Open", "Closed"}}
+	New(sheet, "B2:D100000").AddValidation(list)
+	New(sheet, "C50").AddValidation(&Validation{Type: ValidateWhole, Formula1: "1", Formula2: "100"})
+	New(sheet, "F:G").AddValidation(list)
+	if len(sheet.Rows) != 15 {
+		t.Errorf("AddValidation should not create cells, but %d rows", len(sheet.Rows))
+	}
+	if v := New(sheet, "C50").ValidationAt(0, 0); v == nil || v.Type != ValidateWhole {
+		t.Errorf("validation of C50 should be replaced, but %v", v)
+	}
+	if v := New(sheet, "C51").ValidationAt(0, 0); v == nil || v.Type != ValidateList {
+		t.Errorf("validation below C50 should be kept, but %v", v)
+	}
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	xml := parts["xl/worksheets/sheet1.xml"]
+	if count := strings.Count(xml, "<dataValidation "); count != 2 {
+		t.Errorf("validations of the same rule should be one element, but %d elements", count)
+	}
+	if !strings.Contains(xml, `<dataValidations count="2">`) ||
+		!strings.Contains(xml, `sqref="F1:G1048576 B2:B100000 C2:C49 D2:D100000 C51:C100000"`) ||
+		!strings.Contains(xml, `sqref="C50"`) {
+		t.Errorf("sqref should cover the ranges, but %s", xml)
+	}
+	if sqref := compressSqref([]string{"B3:B4", "C2:C4", "B2", "D2:D4"}); sqref != "B2:D4" {
+		t.Errorf("sqref should be one rectangle, but %s", sqref)
+	}
+
+	var buffer bytes.Buffer
+	if err := Write(file, &buffer); err != nil {
+		t.Fatalf("Write should succeed, but %s", err.Error())
+	}
+	loaded, err := xlsx.OpenBinary(buffer.Bytes())
+	if err != nil {
+		t.Fatalf("written file should be readable, but %s", err.Error())
+	}
+	if v := New(loaded.Sheet["Sheet 1"], "G500").ValidationAt(0, 0); v == nil || v.Type != ValidateList {
+		t.Errorf("merged validation should be read back, but %v", v)
+	}
+}
+
+func TestAddValidationError(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	if err := New(sheet, "A1").AddValidation(&Validation{Type: ValidateList}); err == nil {
+		t.Errorf("AddValidation should return error when list doesn't have choices")
+	}
+	if err := New(sheet, "A1").AddValidation(&Validation{Type: ValidateDecimal, Formula1: "0"}); err == nil {
+		t.Errorf("AddValidation should return error when between doesn't have Formula2")
+	}
+	if err := New(sheet, "A1").AddValidation(&Validation{Type: ValidateList, List: []string{"a,b"}}); err == nil {
+		t.Errorf("AddValidation should return error when list item has comma")
+	}
+}
+
+func TestValidations(t *testing.T) {
+	file := createFile()
+	sheet := file.Sheet["Sheet 1"]
+	New(sheet, "B2:C4").AddValidation(&Validation{Type: ValidateList, List: []string{"Open", "Closed"}, Prompt: "Select"})
+	New(sheet, "C3").AddValidation(&Validation{Type: ValidateList, ListRange: New(file.Sheet["Sheet 2"], "A1:A5")})
+	New(sheet, "F:F").AddValidation(&Validation{Type: ValidateTextLength, Operator: OperatorLessThanOrEqual, Formula1: "10"})
+
+	validations := New(sheet, "A1:F5").Validations()
+	if len(validations) != 5 {
+		t.Fatalf("Validations should return 5 areas, but %d", len(validations))
+	}
+	if validations[0].Range.Format(false) != "B2:B4" || validations[0].Validation.Prompt != "Select" {
+		t.Errorf("first validation should be B2:B4 with prompt, but %s", validations[0].Range.Format(false))
+	}
+	if list := validations[0].Validation.List; len(list) != 2 || list[1] != "Closed" {
+		t.Errorf("list should be read back, but %v", list)
+	}
+	if validations[2].Range.Format(false) != "C3" || validations[2].Validation.ListRange.Format(true) != "Sheet 2!A1:A5" {
+		t.Errorf("list range should be read back, but %s", validations[2].Range.Format(false))
+	}
+	if validations[4].Range.Format(false) != "F1:F5" || validations[4].Validation.Type != ValidateTextLength {
+		t.Errorf("column validation should be read back, but %s", validations[4].Range.Format(false))
+	}
+	if v := New(sheet, "A1").ValidationAt(0, 0); v != nil {
+		t.Errorf("ValidationAt should return nil for cell without validation")
+	}
+
+	New(sheet, "B2:B4").RemoveValidations()
+	if len(New(sheet, "B1:B5").Validations()) != 0 {
+		t.Errorf("RemoveValidations should remove validations")
+	}
+}
+
+func TestReferenceFormula(t *testing.T) {
+	file := createFile()
+	if formula := referenceFormula(New(file.Sheet["Sheet 1"], "B2:C3")); formula != "'Sheet 1'!$B$2:$C$3" {
+		t.Errorf("sheet name with space should be quoted, but %s", formula)
+	}
+	file.Sheet["Sheet 1"].Name = "Data"
+	if formula := referenceFormula(New(file.Sheet["Sheet 1"], "A:A")); formula != "Data!$A:$A" {
+		t.Errorf("simple sheet name should not be quoted, but %s", formula)
+	}
+	file.Sheet["Sheet 1"].Name = "It's"
+	if formula := referenceFormula(New(file.Sheet["Sheet 1"], "A1")); formula != "'It''s'!$A$1" {
+		t.Errorf("quote in sheet name should be escaped, but %s", formula)
+	}
+}
@@ -0,0 +1,84 @@
+package xlsxrange
+
+import (
+	"fmt"
+
+	"github.com/tealeg/xlsx"
+)
+
+// SetAutoFilter sets autofilter of the sheet to selected range. The first row is used as header row.
+//
+// A sheet can have only one autofilter, so existing one is replaced.
+// AllRows and AllColumns are clamped to the used area.
+// It returns error if the range overlaps a table, because tables have their own autofilters.
+func (r *Range) SetAutoFilter() error {
+	area := r.clamp()
+	rowCount, columnCount := area.size()
+	filter := New(r.Sheet, area.Row, area.Column, max(rowCount, 1), max(columnCount, 1))
+	if tables := filter.Tables(); len(tables) > 0 {
+		return fmt.Errorf("Autofilter %s overlaps table '%s'", filter.Format(false), tables[0].Name)
+	}
+	r.Sheet.AutoFilter = &xlsx.AutoFilter{
+		TopLeftCell:     cellName(filter.Row, filter.Column),
+		BottomRightCell: cellName(filter.Row+filter.NumRows-1, filter.Column+filter.NumColumns-1),
+	}
+	return nil
+}
+
+// RemoveAutoFilter removes autofilter of the sheet
+func (r *Range) RemoveAutoFilter() {
+	r.Sheet.AutoFilter = nil
+}
+
+// AutoFilter returns the range of autofilter of the sheet. It returns nil if the sheet doesn't have autofilter.
+func (r *Range) AutoFilter() *Range {
+	if r.Sheet.AutoFilter == nil {
+		return nil
+	}
+	_, position, err := ParseA1Notation(r.Sheet.AutoFilter.TopLeftCell + ":" + r.Sheet.AutoFilter.BottomRightCell)
+	if err != nil {
+		return nil
+	}
+	return New(r.Sheet, position[0], position[1], position[2], position[3])
+}
+
+// FreezePanes freezes rows above and columns left of the left top cell of selected range.
+//
+//	xlsxrange.New(sheet, "B2").FreezePanes() // freezes the first row and column A
+func (r *Range) FreezePanes() {
+	rows := r.Row - 1
+	columns := r.Column - 1
+	if len(r.Sheet.SheetViews) == 0 {
+		r.Sheet.SheetViews = []xlsx.SheetView{{}}
+	}
+	if rows == 0 && columns == 0 {
+		r.Sheet.SheetViews[0].Pane = nil
+		return
+	}
+	activePane := "bottomRight"
+	if columns == 0 {
+		activePane = "bottomLeft"
+	} else if rows == 0 {
+		activePane = "topRight"
+	}
+	r.Sheet.SheetViews[0].Pane = &xlsx.Pane{
+		XSplit:      float64(columns),
+		YSplit:      float64(rows),
+		TopLeftCell: cellName(rows+1, columns+1),
+		ActivePane:  activePane,
+		State:       "frozen",
+	}
+}
+
+// FreezeHeader freezes rows until the first row of selected range and columns left of selected range.
+// It is useful to keep header row of a table visible.
+func (r *Range) FreezeHeader() {
+	New(r.Sheet, r.Row+1, r.Column).FreezePanes()
+}
+
+// Unfreeze removes frozen panes of the sheet
+func (r *Range) Unfreeze() {
+	for i := range r.Sheet.SheetViews {
+		r.Sheet.SheetViews[i].Pane = nil
+	}
+}
@@ -0,0 +1,70 @@
+package xlsxrange
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetAutoFilter(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	if err := New(sheet, "A:C").SetAutoFilter(); err != nil {
+		t.Fatalf("SetAutoFilter should succeed, but %s", err.Error())
+	}
+	if filter := New(sheet).AutoFilter(); filter == nil || filter.Format(false) != "A1:C6" {
+		t.Errorf("autofilter should be clamped to used area A1:C6, but %v", filter)
+	}
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	if xml := parts["xl/worksheets/sheet1.xml"]; !strings.Contains(xml, `<autoFilter ref="A1:C6">`) {
+		t.Errorf("sheet should have autoFilter, but %s", xml)
+	}
+	New(sheet).RemoveAutoFilter()
+	if New(sheet).AutoFilter() != nil {
+		t.Errorf("RemoveAutoFilter should remove autofilter")
+	}
+}
+
+func TestSetAutoFilterOverlapsTable(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	if _, err := New(sheet, "A1:B4").AsTable("Sales", nil); err != nil {
+		t.Fatalf("AsTable should succeed, but %s", err.Error())
+	}
+	if err := New(sheet, "B1:C6").SetAutoFilter(); err == nil {
+		t.Errorf("autofilter that overlaps table should be error")
+	}
+	if New(sheet).AutoFilter() != nil {
+		t.Errorf("autofilter should not be set when it overlaps table")
+	}
+	if err := New(sheet, "D1:D6").SetAutoFilter(); err != nil {
+		t.Errorf("autofilter beside table should succeed, but %s", err.Error())
+	}
+}
+
+func TestFreezePanes(t *testing.T) {
+	file := createTableFile()
+	sheet := file.Sheet["Data"]
+	New(sheet, "B3").FreezePanes()
+	pane := sheet.SheetViews[0].Pane
+	if pane == nil || pane.XSplit != 1 || pane.YSplit != 2 || pane.TopLeftCell != "B3" || pane.ActivePane != "bottomRight" || pane.State != "frozen" {
+		t.Errorf("FreezePanes should freeze 2 rows and 1 column, but %v", pane)
+	}
+
+	New(sheet, "A1:C6").FreezeHeader()
+	pane = sheet.SheetViews[0].Pane
+	if pane == nil || pane.XSplit != 0 || pane.YSplit != 1 || pane.TopLeftCell != "A2" || pane.ActivePane != "bottomLeft" {
+		t.Errorf("FreezeHeader should freeze header row, but %v", pane)
+	}
+	parts, _ := MarshallParts(file)
+	if xml := parts["xl/worksheets/sheet1.xml"]; !strings.Contains(xml, `ySplit="1"`) || !strings.Contains(xml, `state="frozen"`) {
+		t.Errorf("sheet should have frozen pane, but %s", xml)
+	}
+
+	New(sheet).Unfreeze()
+	if sheet.SheetViews[0].Pane != nil {
+		t.Errorf("Unfreeze should remove pane")
+	}
+}
@@ -0,0 +1,548 @@
+package xlsxrange
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tealeg/xlsx"
+)
+
+// Some features (conditional formatting and so on) can't be written by tealeg/xlsx.
+// They are kept in this registry and merged into the xlsx package by Write and Save.
+
+type fileExtension struct {
+	sheets map[*xlsx.Sheet]*sheetExtension
+}
+
+type sheetExtension struct {
+	conditionalFormats []*ConditionalFormat
+	tables             []*Table
+	charts             []*ChartBuilder
+	pivotTables        []*PivotBuilder
+	collapsedRows      map[int]bool // Summary rows (1 origin) of collapsed row groups
+	printArea          []*Range
+	printTitleRows     *Range
+	printTitleColumns  *Range
+	rowBreaks          []int
+	columnBreaks       []int
+}
+
+var extensions = struct {
+	sync.Mutex
+	files map[*xlsx.File]*fileExtension
+}{
+	files: make(map[*xlsx.File]*fileExtension),
+}
+
+// findSheetExtension returns extension of the sheet or nil. Unlike sheetExtensionOf, it doesn't create new one,
+// so read-only operations don't register the file. Caller should lock extensions.
+func findSheetExtension(sheet *xlsx.Sheet) *sheetExtension {
+	file, ok := extensions.files[sheet.File]
+	if !ok {
+		return nil
+	}
+	return file.sheets[sheet]
+}
+
+// sheetExtensionOf returns extension of the sheet. It creates new one if needed.
+// Caller should lock extensions.
+func sheetExtensionOf(sheet *xlsx.Sheet) *sheetExtension {
+	file, ok := extensions.files[sheet.File]
+	if !ok {
+		file = &fileExtension{sheets: make(map[*xlsx.Sheet]*sheetExtension)}
+		extensions.files[sheet.File] = file
+	}
+	ext, ok := file.sheets[sheet]
+	if !ok {
+		ext = &sheetExtension{}
+		file.sheets[sheet] = ext
+	}
+	return ext
+}
+
+// moveRowFeatures moves autofilter and column validations of the sheet, features registered to the sheet,
+// and chart series and pivot sources that refer the sheet after rows of the sheet are inserted or deleted.
+// moveRange returns moved range or nil if the range is deleted, moveRow returns moved row number (1 origin)
+// or 0 if the row is deleted, and mapFormula moves references in formulas of the sheet.
+func moveRowFeatures(sheet *xlsx.Sheet, moveRange func(*Range) *Range, moveRow func(int) int, mapFormula func(string) string) {
+	if filter := New(sheet).AutoFilter(); filter != nil {
+		sheet.AutoFilter = nil
+		if moved := moveRange(filter); moved != nil {
+			sheet.AutoFilter = &xlsx.AutoFilter{
+				TopLeftCell:     cellName(moved.Row, moved.Column),
+				BottomRightCell: cellName(moved.Row+moved.NumRows-1, moved.Column+moved.NumColumns-1),
+			}
+		}
+	}
+	moveColumnValidations(sheet, moveRange)
+	extensions.Lock()
+	defer extensions.Unlock()
+	file, ok := extensions.files[sheet.File]
+	if !ok {
+		return
+	}
+	for _, ext := range file.sheets {
+		for _, chart := range ext.charts {
+			for i, series := range chart.series {
+				// deleted series keep their ranges like #REF! of formulas
+				if series.Categories != nil && series.Categories.Sheet == sheet {
+					if moved := moveRange(series.Categories); moved != nil {
+						chart.series[i].Categories = moved
+					}
+				}
+				if series.Values.Sheet == sheet {
+					if moved := moveRange(series.Values); moved != nil {
+						chart.series[i].Values = moved
+					}
+				}
+			}
+		}
+		for _, pivot := range ext.pivotTables {
+			if pivot.source.Sheet == sheet {
+				if moved := moveRange(pivot.source); moved != nil {
+					pivot.source = moved
+				}
+			}
+		}
+	}
+	ext := file.sheets[sheet]
+	if ext == nil {
+		return
+	}
+	formats := ext.conditionalFormats[:0]
+	for _, format := range ext.conditionalFormats {
+		if format.Range = moveRange(format.Range); format.Range != nil {
+			for i, rule := range format.Rules {
+				format.Rules[i].Formula1 = mapFormula(rule.Formula1)
+				format.Rules[i].Formula2 = mapFormula(rule.Formula2)
+			}
+			formats = append(formats, format)
+		}
+	}
+	ext.conditionalFormats = formats
+	tables := ext.tables[:0]
+	for _, table := range ext.tables {
+		moved := moveRange(table.Range)
+		if moved == nil {
+			continue
+		}
+		if table.totalsRow != 0 {
+			// data rows end above totals row, so rows inserted before totals row are data rows
+			table.totalsRow = moveRow(table.totalsRow)
+			if table.totalsRow != 0 && moved.NumRows != AllRows {
+				moved.NumRows = table.totalsRow - moved.Row
+			}
+		}
+		table.Range = moved
+		tables = append(tables, table)
+	}
+	ext.tables = tables
+	charts := ext.charts[:0]
+	for _, chart := range ext.charts {
+		if chart.anchor = moveRange(chart.anchor); chart.anchor != nil {
+			charts = append(charts, chart)
+		}
+	}
+	ext.charts = charts
+	pivotTables := ext.pivotTables[:0]
+	for _, pivot := range ext.pivotTables {
+		if pivot.dest = moveRange(pivot.dest); pivot.dest != nil {
+			pivotTables = append(pivotTables, pivot)
+		}
+	}
+	ext.pivotTables = pivotTables
+	printArea := ext.printArea[:0]
+	for _, area := range ext.printArea {
+		if moved := moveRange(area); moved != nil {
+			printArea = append(printArea, moved)
+		}
+	}
+	ext.printArea = printArea
+	if ext.printTitleRows != nil {
+		ext.printTitleRows = moveRange(ext.printTitleRows)
+	}
+	breaks := ext.rowBreaks[:0]
+	for _, id := range ext.rowBreaks {
+		// break ID is the last row before the break, and the break moves with the row after it
+		if moved := moveRow(id + 1); moved != 0 {
+			breaks = append(breaks, moved-1)
+		}
+	}
+	ext.rowBreaks = breaks
+	collapsedRows := make(map[int]bool)
+	for row := range ext.collapsedRows {
+		if moved := moveRow(row); moved != 0 {
+			collapsedRows[moved] = true
+		}
+	}
+	ext.collapsedRows = collapsedRows
+}
+
+// Release discards features (conditional formatting and so on) registered to the file.
+// The registry refers the file until it is called, so call it when the file is not needed anymore
+// to release memory.
+func Release(file *xlsx.File) {
+	extensions.Lock()
+	defer extensions.Unlock()
+	delete(extensions.files, file)
+}
+
+// Save writes the file to path with features that are added by this package.
+// Use it instead of xlsx.File.Save, which drops them.
+func Save(file *xlsx.File, path string) error {
+	target, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = Write(file, target)
+	if err != nil {
+		target.Close()
+		return err
+	}
+	return target.Close()
+}
+
+// Write writes the file as xlsx with features that are added by this package
+// (like conditional formatting). Use it instead of xlsx.File.Write.
+func Write(file *xlsx.File, w io.Writer) error {
+	parts, err := MarshallParts(file)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(parts))
+	for name := range parts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	zipWriter := zip.NewWriter(w)
+	for _, name := range names {
+		partWriter, err := zipWriter.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(partWriter, parts[name]); err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
+
+// MarshallParts returns map of part names and XML contents like xlsx.File.MarshallParts,
+// with features that are added by this package.
+func MarshallParts(file *xlsx.File) (map[string]string, error) {
+	extensions.Lock()
+	defer extensions.Unlock()
+	ext, ok := extensions.files[file]
+	if !ok {
+		ext = &fileExtension{}
+	}
+	parts, err := file.MarshallParts()
+	if err != nil {
+		return nil, err
+	}
+	builder := &packageBuilder{parts: parts, relationships: make(map[string][]relationship)}
+	for i, sheet := range file.Sheets {
+		partName := fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)
+		parts[partName], err = mergeDataValidations(parts[partName])
+		if err != nil {
+			return nil, err
+		}
+		sheetExt, ok := ext.sheets[sheet]
+		if !ok {
+			parts[partName] = writeRowAttributes(parts[partName], sheet, nil)
+			continue
+		}
+		parts[partName] = writeRowAttributes(parts[partName], sheet, sheetExt.collapsedRows)
+		var children []string
+		for _, format := range sheetExt.conditionalFormats {
+			child, err := builder.conditionalFormatting(format)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+		}
+		if len(sheetExt.tables) > 0 {
+			child, err := builder.tableParts(partName, sheetExt.tables)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+		}
+		children = append(children, pageBreaks(sheetExt)...)
+		if len(sheetExt.charts) > 0 {
+			children = append(children, builder.drawing(partName, sheetExt.charts))
+		}
+		for _, pivot := range sheetExt.pivotTables {
+			if err := builder.pivotTable(partName, pivot); err != nil {
+				return nil, err
+			}
+		}
+		builder.addPrintNames(i, sheetExt)
+		parts[partName], err = insertWorksheetChildren(parts[partName], children)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := builder.finish(); err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+// packageBuilder collects workbook wide information while sheets are processed
+type packageBuilder struct {
+	parts         map[string]string
+	dxfs          []string
+	priority      int
+	tableCount    int
+	relationships map[string][]relationship // source part name -> relationships
+	contentTypes  []string                  // Override elements
+	definedNames  []string                  // definedName elements of the workbook
+	pivotCaches   []string                  // pivotCache elements of the workbook
+	partCount     map[string]int
+}
+
+type relationship struct {
+	XMLName xml.Name `xml:"Relationship"`
+	ID      string   `xml:"Id,attr"`
+	Type    string   `xml:"Type,attr"`
+	Target  string   `xml:"Target,attr"`
+}
+
+const relationshipNamespace = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+const relationshipBase = relationshipNamespace + "/"
+
+// addPart adds new part. name should have "%d" that is replaced with sequence number
+// like "xl/tables/table%d.xml". It returns actual part name.
+func (b *packageBuilder) addPart(name, contentType, content string) string {
+	if b.partCount == nil {
+		b.partCount = make(map[string]int)
+	}
+	b.partCount[name]++
+	partName := fmt.Sprintf(name, b.partCount[name])
+	b.parts[partName] = xml.Header + content
+	b.contentTypes = append(b.contentTypes, fmt.Sprintf(`<Override PartName="/%s" ContentType="%s"/>`, partName, contentType))
+	return partName
+}
+
+var relationshipIDPattern = regexp.MustCompile(`Id="rId(\d+)"`)
+
+// addRelationship adds relationship from source part to target part. It returns relationship ID.
+// IDs of relationships that are written by tealeg/xlsx (like the ones of the workbook) are not reused.
+func (b *packageBuilder) addRelationship(source, relationshipType, target string) string {
+	last := len(b.relationships[source])
+	for _, match := range relationshipIDPattern.FindAllStringSubmatch(b.parts[relationshipsPart(source)], -1) {
+		if number, _ := strconv.Atoi(match[1]); number > last {
+			last = number
+		}
+	}
+	id := fmt.Sprintf("rId%d", last+1)
+	b.relationships[source] = append(b.relationships[source], relationship{
+		ID:     id,
+		Type:   relationshipBase + relationshipType,
+		Target: relativePath(source, target),
+	})
+	return id
+}
+
+// relationshipsPart returns name of relationships part of source part
+func relationshipsPart(source string) string {
+	index := strings.LastIndex(source, "/")
+	return source[:index] + "/_rels/" + source[index+1:] + ".rels"
+}
+
+// relativePath returns path of target part from the directory of source part
+func relativePath(source, target string) string {
+	sourceDir := strings.Split(source, "/")
+	sourceDir = sourceDir[:len(sourceDir)-1]
+	targetPath := strings.Split(target, "/")
+	common := 0
+	for common < len(sourceDir) && common < len(targetPath)-1 && sourceDir[common] == targetPath[common] {
+		common++
+	}
+	return strings.Repeat("../", len(sourceDir)-common) + strings.Join(targetPath[common:], "/")
+}
+
+// finish writes workbook wide information
+func (b *packageBuilder) finish() error {
+	for source, relationships := range b.relationships {
+		relsName := relationshipsPart(source)
+		if existing, ok := b.parts[relsName]; ok {
+			// relationships written by tealeg/xlsx are kept
+			content, err := marshalXML(relationships)
+			if err != nil {
+				return err
+			}
+			index := strings.LastIndex(existing, "</Relationships>")
+			if index == -1 {
+				return fmt.Errorf("%s doesn't have Relationships element", relsName)
+			}
+			b.parts[relsName] = existing[:index] + content + existing[index:]
+			continue
+		}
+		content, err := marshalXML(struct {
+			XMLName       xml.Name `xml:"http://schemas.openxmlformats.org/package/2006/relationships Relationships"`
+			Relationships []relationship
+		}{Relationships: relationships})
+		if err != nil {
+			return err
+		}
+		b.parts[relsName] = xml.Header + content
+	}
+	if len(b.contentTypes) > 0 {
+		types := b.parts["[Content_Types].xml"]
+		index := strings.LastIndex(types, "</Types>")
+		if index == -1 {
+			return fmt.Errorf("[Content_Types].xml doesn't have Types element")
+		}
+		b.parts["[Content_Types].xml"] = types[:index] + strings.Join(b.contentTypes, "") + types[index:]
+	}
+	if len(b.definedNames) > 0 {
+		workbook := strings.Replace(b.parts["xl/workbook.xml"], "<definedNames></definedNames>", "", 1)
+		index := strings.Index(workbook, "<calcPr")
+		if index == -1 {
+			index = strings.LastIndex(workbook, "</workbook>")
+		}
+		if index == -1 {
+			return fmt.Errorf("xl/workbook.xml doesn't have workbook element")
+		}
+		definedNames := "<definedNames>" + strings.Join(b.definedNames, "") + "</definedNames>"
+		b.parts["xl/workbook.xml"] = workbook[:index] + definedNames + workbook[index:]
+	}
+	if len(b.pivotCaches) > 0 {
+		// pivotCaches follows calcPr, which is the last element written by tealeg/xlsx
+		workbook := b.parts["xl/workbook.xml"]
+		index := strings.LastIndex(workbook, "</workbook>")
+		if index == -1 {
+			return fmt.Errorf("xl/workbook.xml doesn't have workbook element")
+		}
+		pivotCaches := "<pivotCaches>" + strings.Join(b.pivotCaches, "") + "</pivotCaches>"
+		b.parts["xl/workbook.xml"] = workbook[:index] + pivotCaches + workbook[index:]
+	}
+	if len(b.dxfs) > 0 {
+		styles := b.parts["xl/styles.xml"]
+		dxfs := fmt.Sprintf(`<dxfs count="%d">%s</dxfs>`, len(b.dxfs), strings.Join(b.dxfs, ""))
+		index := strings.LastIndex(styles, "</styleSheet>")
+		if index == -1 {
+			return fmt.Errorf("xl/styles.xml doesn't have styleSheet element")
+		}
+		b.parts["xl/styles.xml"] = styles[:index] + dxfs + styles[index:]
+	}
+	return nil
+}
+
+// worksheetElementOrder is the order of child elements of worksheet defined in ECMA-376
+var worksheetElementOrder = []string{
+	"sheetPr", "dimension", "sheetViews", "sheetFormatPr", "cols", "sheetData", "sheetCalcPr",
+	"sheetProtection", "protectedRanges", "scenarios", "autoFilter", "sortState", "dataConsolidate",
+	"customSheetViews", "mergeCells", "phoneticPr", "conditionalFormatting", "dataValidations",
+	"hyperlinks", "printOptions", "pageMargins", "pageSetup", "headerFooter", "rowBreaks", "colBreaks",
+	"customProperties", "cellWatches", "ignoredErrors", "smartTags", "drawing", "legacyDrawing",
+	"legacyDrawingHF", "picture", "oleObjects", "controls", "webPublishItems", "tableParts", "extLst",
+}
+
+type xmlChild struct {
+	name string
+	raw  string
+}
+
+// insertWorksheetChildren inserts raw XML elements into worksheet XML.
+// Child elements are sorted in the order of the schema.
+func insertWorksheetChildren(worksheet string, children []string) (string, error) {
+	if len(children) == 0 {
+		return worksheet, nil
+	}
+	head, existing, tail, err := splitChildren(worksheet)
+	if err != nil {
+		return "", err
+	}
+	for _, child := range children {
+		_, parsed, _, err := splitChildren("<root>" + child + "</root>")
+		if err != nil {
+			return "", err
+		}
+		existing = append(existing, parsed...)
+	}
+	order := make(map[string]int)
+	for i, name := range worksheetElementOrder {
+		order[name] = i
+	}
+	sort.SliceStable(existing, func(i, j int) bool {
+		return order[existing[i].name] < order[existing[j].name]
+	})
+	if !strings.Contains(head, "xmlns:r=") {
+		head = strings.Replace(head, "<worksheet ", `<worksheet xmlns:r="`+relationshipNamespace+`" `, 1)
+	}
+	var buffer bytes.Buffer
+	buffer.WriteString(head)
+	for _, child := range existing {
+		buffer.WriteString(child.raw)
+	}
+	buffer.WriteString(tail)
+	return buffer.String(), nil
+}
+
+// splitChildren splits XML document into root start tag part, child elements and root end tag part.
+func splitChildren(document string) (string, []xmlChild, string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	var children []xmlChild
+	depth := 0
+	headEnd := -1
+	childStart := 0
+	childName := ""
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return "", nil, "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				headEnd = int(decoder.InputOffset())
+			} else if depth == 2 {
+				childStart = offset
+				childName = t.Name.Local
+			}
+		case xml.EndElement:
+			if depth == 2 {
+				children = append(children, xmlChild{name: childName, raw: document[childStart:decoder.InputOffset()]})
+			} else if depth == 1 {
+				return document[:headEnd], children, document[offset:], nil
+			}
+			depth--
+		}
+	}
+	return "", nil, "", fmt.Errorf("XML document doesn't have root element")
+}
+
+// sqref returns reference of selected range for sqref attributes.
+// Whole rows and columns are kept only if they start from the first row or column
+// because A1 notation can't express them otherwise.
+func (r *Range) sqref() string {
+	if (r.NumRows == AllRows && r.Row != 1) || (r.NumColumns == AllColumns && r.Column != 1) {
+		return r.clamp().Format(false)
+	}
+	return r.Format(false)
+}
+
+// marshalXML marshals value without XML header
+func marshalXML(value interface{}) (string, error) {
+	data, err := xml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,82 @@
+package xlsxrange
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+// createWritableFile creates same cells as createFile via tealeg/xlsx API to marshal it.
+func createWritableFile() *xlsx.File {
+	file := xlsx.NewFile()
+	for s := 1; s < 4; s++ {
+		sheet, _ := file.AddSheet(fmt.Sprintf("Sheet %d", s))
+		for row := 0; row < 15; row++ {
+			for column := 0; column < 10; column++ {
+				sheet.Cell(row, column).SetString(cellName(row+1, column+1))
+			}
+		}
+	}
+	return file
+}
+
+func TestWrite(t *testing.T) {
+	file := createWritableFile()
+	sheet := file.Sheet["Sheet 1"]
+	New(sheet, "A1:B2").Merge()
+	New(sheet, "C3").GetCell().SetDataValidation(xlsx.NewXlsxCellDataValidation(true))
+	New(sheet, "C1:C10").AddConditionalFormat(ConditionalRule{Type: RuleDataBar, Colors: []string{"FF638EC6"}})
+
+	parts, err := MarshallParts(file)
+	if err != nil {
+		t.Fatalf("MarshallParts should succeed, but %s", err.Error())
+	}
+	xml := parts["xl/worksheets/sheet1.xml"]
+	merge := strings.Index(xml, "<mergeCells")
+	conditional := strings.Index(xml, "<conditionalFormatting")
+	validation := strings.Index(xml, "<dataValidations")
+	if !(merge < conditional && conditional < validation) {
+		t.Errorf("elements should be sorted in schema order, but %d %d %d", merge, conditional, validation)
+	}
+
+	var buffer bytes.Buffer
+	if err := Write(file, &buffer); err != nil {
+		t.Fatalf("Write should succeed, but %s", err.Error())
+	}
+	reopened, err := xlsx.OpenBinary(buffer.Bytes())
+	if err != nil {
+		t.Fatalf("written file should be readable, but %s", err.Error())
+	}
+	if value := reopened.Sheet["Sheet 1"].Cell(4, 2).Value; value != "C5" {
+		t.Errorf("cell value should be kept, but %s", value)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	file := createWritableFile()
+	New(file.Sheet["Sheet 1"], "A1").AddConditionalFormat(ConditionalRule{Type: RuleDataBar, Colors: []string{"FF638EC6"}})
+	Release(file)
+	parts, _ := MarshallParts(file)
+	if strings.Contains(parts["xl/worksheets/sheet1.xml"], "conditionalFormatting") {
+		t.Errorf("Release should discard conditional formatting")
+	}
+}
+
+// registered returns true if the file is in the registry
+func registered(file *xlsx.File) bool {
+	extensions.Lock()
+	defer extensions.Unlock()
+	_, ok := extensions.files[file]
+	return ok
+}
+
+func TestReadOnlyAccessDoesntRegisterFile(t *testing.T) {
+	file := createWritableFile()
+	New(file.Sheet["Sheet 1"], "A1:B3").ConditionalFormats()
+	if registered(file) {
+		t.Errorf("read-only access should not register the file")
+	}
+}